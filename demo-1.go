@@ -1,10 +1,14 @@
 package main
 
 import (
+	"fmt"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"uber-zao-demo/logging"
 )
 
 func main() {
@@ -18,15 +22,19 @@ func main() {
 	// created by different methods has different settings. Example is suitable for use in test code, Development is
 	// used in the development environment, and Production is used in the production environment.
 	// If you want to customize the logger, you can call the `zap.New()` method to create it.
-	config := zap.NewProductionConfig()
-	config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(time.RFC3339)
-	logger, err := config.Build()
+	//
+	// logging.NewLogger wraps zap.NewProductionConfig with the RFC3339 timestamp format. Production configs
+	// sample repeated entries; WithSamplingExcept keeps the same sampling but never drops Error and above.
+	logger, err := logging.NewLogger(logging.WithSamplingExcept(zapcore.ErrorLevel, 100, 100))
 	if err != nil {
 		panic(err)
 	}
 	defer func(logger *zap.Logger) {
-		// flushes buffer, if any
-		_ = logger.Sync()
+		// flushes buffer, if any. logger.Sync() reports "sync /dev/stderr: invalid argument" when stderr
+		// is a terminal or a pipe; SafeSync ignores that case so that real failures are not drowned out.
+		if err := logging.SafeSync(logger); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to sync logger:", err)
+		}
 	}(logger)
 
 	// the output is like this:
@@ -38,6 +46,16 @@ func main() {
 		zap.Duration("backoff", time.Second),
 	)
 
+	// logging.Namespaced nests related fields under one key, the output is like this:
+	// {"level":"info","ts":"2023-10-24T11:06:18+08:00","caller":"uber-zap-demo/demo-1.go:49","msg":"failed to fetch URL","request":{"url":"http://marmotedu.com","attempt":3,"backoff":1}}
+	logger.Info("failed to fetch URL",
+		logging.Namespaced("request",
+			zap.String("url", url),
+			zap.Int("attempt", 3),
+			zap.Duration("backoff", time.Second),
+		),
+	)
+
 	// why use sugar logger? It supports Info, Infow, Infof forms.
 	// The meaning of w in infow ? With Compared with info, infow does not need to use zap.String, zap.Int, zap.Duration and other functions.
 	// When we have higher performance requirements for logs, we can use Logger instead of SugaredLogger. Logger
@@ -55,6 +73,11 @@ func main() {
 		"backoff", time.Second,
 	)
 
+	// Infof formats its message even if Info is disabled. When the arguments are expensive to produce, guard the
+	// call with logging.InfoEnabled, which checks the logger's current level.
+	//
 	// {"level":"info","ts":"2023-10-24T11:06:18+08:00","caller":"uber-zap-demo/demo-1.go:45","msg":"failed to fetch URL: http://marmotedu.com"}
-	sugar.Infof("failed to fetch URL: %s", url)
+	if logging.InfoEnabled(logger) {
+		sugar.Infof("failed to fetch URL: %s", url)
+	}
 }
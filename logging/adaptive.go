@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithAdaptiveLevel protects the sinks while something is going wrong: once
+// more than errorThreshold entries at ErrorLevel or above are written within
+// window, Debug and Info entries are dropped for cooldown, after which the
+// configured level applies again. Warn entries and above, the errors
+// themselves included, are never dropped. Errors logged during the cooldown
+// are counted too, so a sustained error rate keeps the level raised.
+//
+// Windows are measured in entry time, like sampling.
+func WithAdaptiveLevel(errorThreshold int, window, cooldown time.Duration) Option {
+	return func(o *options) {
+		a := &adaptiveLevel{threshold: errorThreshold, window: window, cooldown: cooldown}
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &adaptiveLevelCore{Core: core, level: a}
+		})
+	}
+}
+
+type adaptiveLevel struct {
+	threshold        int
+	window, cooldown time.Duration
+
+	mu sync.Mutex
+	// errors holds the times of the errors within the current window, at
+	// most threshold+1 of them.
+	errors      []time.Time
+	raisedUntil time.Time
+}
+
+// raised reports whether entries below WarnLevel are dropped at now.
+func (a *adaptiveLevel) raised(now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return now.Before(a.raisedUntil)
+}
+
+// recordError counts an error entry logged at now.
+func (a *adaptiveLevel) recordError(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	start := now.Add(-a.window)
+	kept := a.errors[:0]
+	for _, t := range a.errors {
+		if t.After(start) {
+			kept = append(kept, t)
+		}
+	}
+	a.errors = append(kept, now)
+	if len(a.errors) > a.threshold {
+		a.raisedUntil = now.Add(a.cooldown)
+		a.errors = a.errors[:0]
+	}
+}
+
+type adaptiveLevelCore struct {
+	zapcore.Core
+	level *adaptiveLevel
+}
+
+func (c *adaptiveLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &adaptiveLevelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *adaptiveLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if ent.Level < zapcore.WarnLevel && c.level.raised(ent.Time) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *adaptiveLevelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level >= zapcore.ErrorLevel {
+		c.level.recordError(ent.Time)
+	}
+	return c.Core.Write(ent, fields)
+}
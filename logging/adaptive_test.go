@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// adaptiveStep is an entry logged at a time relative to the test's start.
+type adaptiveStep struct {
+	at    time.Duration
+	level zapcore.Level
+	msg   string
+}
+
+// errorsAt returns n ErrorLevel steps at the given time.
+func errorsAt(at time.Duration, n int) []adaptiveStep {
+	steps := make([]adaptiveStep, n)
+	for i := range steps {
+		steps[i] = adaptiveStep{at, zapcore.ErrorLevel, fmt.Sprintf("error %v/%d", at, i)}
+	}
+	return steps
+}
+
+func TestWithAdaptiveLevel(t *testing.T) {
+	start := time.Date(2023, 10, 24, 11, 6, 18, 0, time.UTC)
+	concat := func(parts ...[]adaptiveStep) []adaptiveStep {
+		var all []adaptiveStep
+		for _, p := range parts {
+			all = append(all, p...)
+		}
+		return all
+	}
+	tests := []struct {
+		name  string
+		steps []adaptiveStep
+		// dropped lists the messages that must not be written.
+		dropped []string
+	}{
+		{
+			name: "flood",
+			steps: concat(
+				[]adaptiveStep{{0, zapcore.InfoLevel, "before"}},
+				errorsAt(0, 4),
+				[]adaptiveStep{
+					{time.Second, zapcore.InfoLevel, "during"},
+					{time.Second, zapcore.WarnLevel, "warn during"},
+					{2 * time.Second, zapcore.ErrorLevel, "error during"},
+					{11 * time.Second, zapcore.InfoLevel, "after"},
+				},
+			),
+			dropped: []string{"during"},
+		},
+		{
+			name: "at the threshold",
+			steps: concat(
+				errorsAt(0, 3),
+				[]adaptiveStep{{time.Second, zapcore.InfoLevel, "kept"}},
+			),
+		},
+		{
+			name: "spread out",
+			steps: concat(
+				errorsAt(0, 2),
+				errorsAt(1500*time.Millisecond, 2),
+				[]adaptiveStep{{2 * time.Second, zapcore.InfoLevel, "kept"}},
+			),
+		},
+		{
+			name: "sustained",
+			steps: concat(
+				errorsAt(0, 4),
+				errorsAt(9*time.Second, 4),
+				[]adaptiveStep{
+					{12 * time.Second, zapcore.InfoLevel, "extended"},
+					{20 * time.Second, zapcore.InfoLevel, "after"},
+				},
+			),
+			dropped: []string{"extended"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &stepClock{now: start}
+			logger, entries := newFileLogger(t, WithSampling(0, 0), WithClock(clock),
+				WithAdaptiveLevel(3, time.Second, 10*time.Second))
+			dropped := make(map[string]bool)
+			for _, msg := range tt.dropped {
+				dropped[msg] = true
+			}
+			var want []interface{}
+			for _, s := range tt.steps {
+				clock.now = start.Add(s.at)
+				logger.Check(s.level, s.msg).Write()
+				if !dropped[s.msg] {
+					want = append(want, s.msg)
+				}
+			}
+
+			var got []interface{}
+			for _, e := range entries() {
+				got = append(got, e["msg"])
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("written %v, want %v", got, want)
+			}
+		})
+	}
+}
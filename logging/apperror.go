@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Coded is implemented by application errors that carry a machine-readable
+// code, such as "ORDER_NOT_FOUND", and the category it belongs to, such as
+// "not_found" or "validation".
+type Coded interface {
+	Code() string
+	Category() string
+}
+
+// AppError logs err as an object instead of a plain string, so that its code
+// and category stay queryable:
+//
+//	"error":{"message":"load order 42: order not found","code":"ORDER_NOT_FOUND","category":"not_found"}
+//
+// The message is err.Error(), and the code and category come from the first
+// error in err's chain implementing Coded, found with errors.As, so an error
+// wrapped with fmt.Errorf and %w keeps them. Without a Coded error in the
+// chain only the message is logged; a nil err logs nothing.
+func AppError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, appError{err})
+}
+
+type appError struct {
+	err error
+}
+
+func (e appError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", e.err.Error())
+	var coded Coded
+	if errors.As(e.err, &coded) {
+		enc.AddString("code", coded.Code())
+		enc.AddString("category", coded.Category())
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// codedError is an application error with a code and category.
+type codedError struct {
+	code, category string
+}
+
+func (e *codedError) Error() string    { return "order not found" }
+func (e *codedError) Code() string     { return e.code }
+func (e *codedError) Category() string { return e.category }
+
+func TestAppError(t *testing.T) {
+	notFound := &codedError{code: "ORDER_NOT_FOUND", category: "not_found"}
+	tests := []struct {
+		name string
+		err  error
+		// want is the logged object, nil if the field is left out.
+		want interface{}
+	}{
+		{
+			name: "coded",
+			err:  notFound,
+			want: map[string]interface{}{"message": "order not found", "code": "ORDER_NOT_FOUND", "category": "not_found"},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("load order 42: %w", notFound),
+			want: map[string]interface{}{"message": "load order 42: order not found", "code": "ORDER_NOT_FOUND", "category": "not_found"},
+		},
+		{
+			name: "joined",
+			err:  errors.Join(errors.New("retry failed"), notFound),
+			want: map[string]interface{}{"message": "retry failed\norder not found", "code": "ORDER_NOT_FOUND", "category": "not_found"},
+		},
+		{
+			name: "plain",
+			err:  errors.New("connection refused"),
+			want: map[string]interface{}{"message": "connection refused"},
+		},
+		{
+			name: "nil",
+			err:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			logger.Error("request failed", AppError("error", tt.err))
+
+			got, ok := entries()[0]["error"]
+			if tt.want == nil {
+				if ok {
+					t.Errorf("error = %v, want it left out", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// NewAsyncCore moves the work of inner off the logging goroutine: entries are
+// queued, up to bufferSize of them, and written to inner by a background
+// goroutine, which also syncs inner every flushInterval. When the queue is
+// full an entry is written synchronously instead, so nothing is dropped.
+//
+// Sync waits until every entry queued before it has been written, then syncs
+// inner. DPanic, Panic and Fatal entries are not queued: the program may
+// panic or exit as soon as they are logged, so they are written and inner
+// synced before the log call returns, after the entries queued before them. The returned stop function writes what is still queued and ends the
+// background goroutine; the core keeps working afterwards, writing
+// synchronously.
+//
+// Fields are encoded by the background goroutine, so values passed by
+// reference, such as with zap.Any or zap.Object, must not be modified after
+// the log call.
+func NewAsyncCore(inner zapcore.Core, bufferSize int, flushInterval time.Duration) (zapcore.Core, func()) {
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	q := &asyncQueue{
+		items: make(chan asyncItem, bufferSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go q.run(inner, flushInterval)
+	return &asyncCore{Core: inner, queue: q}, q.close
+}
+
+type asyncItem struct {
+	core   zapcore.Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+	// synced, when set, marks a Sync waiting for the items before it.
+	synced chan struct{}
+}
+
+type asyncQueue struct {
+	items chan asyncItem
+
+	// mu is held for reading while queuing, and for writing to stop the
+	// queue, so that no item is queued after the final drain.
+	mu      sync.RWMutex
+	stopped bool
+	once    sync.Once
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func (q *asyncQueue) run(inner zapcore.Core, flushInterval time.Duration) {
+	defer close(q.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case it := <-q.items:
+			it.handle()
+		case <-ticker.C:
+			_ = inner.Sync()
+		case <-q.stop:
+			for {
+				select {
+				case it := <-q.items:
+					it.handle()
+				default:
+					_ = inner.Sync()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (it asyncItem) handle() {
+	if it.synced != nil {
+		close(it.synced)
+		return
+	}
+	_ = it.core.Write(it.ent, it.fields)
+}
+
+func (q *asyncQueue) close() {
+	q.once.Do(func() {
+		q.mu.Lock()
+		q.stopped = true
+		q.mu.Unlock()
+		close(q.stop)
+	})
+	<-q.done
+}
+
+type asyncCore struct {
+	zapcore.Core
+	queue *asyncQueue
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{Core: c.Core.With(fields), queue: c.queue}
+}
+
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *asyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level > zapcore.ErrorLevel {
+		// Like zapcore's own core, sync before a Panic or Fatal entry ends
+		// the program, with the entries ahead of it written first.
+		c.drain()
+		if err := c.Core.Write(ent, fields); err != nil {
+			return err
+		}
+		return c.Core.Sync()
+	}
+	c.queue.mu.RLock()
+	if !c.queue.stopped {
+		select {
+		case c.queue.items <- asyncItem{core: c.Core, ent: ent, fields: fields}:
+			c.queue.mu.RUnlock()
+			return nil
+		default:
+		}
+	}
+	c.queue.mu.RUnlock()
+	return c.Core.Write(ent, fields)
+}
+
+func (c *asyncCore) Sync() error {
+	c.drain()
+	return c.Core.Sync()
+}
+
+// drain waits until every entry queued so far has been written.
+func (c *asyncCore) drain() {
+	c.queue.mu.RLock()
+	if c.queue.stopped {
+		c.queue.mu.RUnlock()
+		return
+	}
+	synced := make(chan struct{})
+	c.queue.items <- asyncItem{synced: synced}
+	c.queue.mu.RUnlock()
+	<-synced
+}
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// messages returns the messages of the JSON entries in ws, one per line.
+func messages(t *testing.T, ws *fakeSyncer) []string {
+	t.Helper()
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(ws.String()), "\n") {
+		if line != "" {
+			msgs = append(msgs, decodeLine(t, line)["msg"].(string))
+		}
+	}
+	return msgs
+}
+
+func TestNewAsyncCoreConcurrent(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+	}{
+		// A small queue fills up, so many entries are written synchronously.
+		{"small buffer", 4},
+		{"large buffer", 4096},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const goroutines, entries = 8, 200
+			ws := newFakeSyncer()
+			core, stop := NewAsyncCore(newSyncerLogger(ws).Core(), tt.bufferSize, time.Millisecond)
+			logger := zap.New(core)
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					child := logger.With(zap.Int("goroutine", g))
+					for i := 0; i < entries; i++ {
+						child.Info(fmt.Sprintf("%d-%d", g, i))
+						if i%50 == 0 {
+							_ = child.Sync()
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			stop()
+
+			seen := map[string]bool{}
+			for _, msg := range messages(t, ws) {
+				if seen[msg] {
+					t.Errorf("entry %q written twice", msg)
+				}
+				seen[msg] = true
+			}
+			if len(seen) != goroutines*entries {
+				t.Errorf("got %d distinct entries, want %d", len(seen), goroutines*entries)
+			}
+		})
+	}
+}
+
+func TestNewAsyncCoreStopDrains(t *testing.T) {
+	ws := newFakeSyncer()
+	core, stop := NewAsyncCore(newSyncerLogger(ws).Core(), 1000, time.Hour)
+	logger := zap.New(core)
+	for i := 0; i < 500; i++ {
+		logger.Info(fmt.Sprint(i))
+	}
+	stop()
+
+	if got := len(messages(t, ws)); got != 500 {
+		t.Errorf("got %d entries after stop, want 500", got)
+	}
+	if ws.syncCount() == 0 {
+		t.Error("stop did not sync the inner core")
+	}
+
+	logger.Info("after stop")
+	stop()
+	if msgs := messages(t, ws); msgs[len(msgs)-1] != "after stop" {
+		t.Error("entry logged after stop was not written synchronously")
+	}
+}
+
+func TestNewAsyncCoreSync(t *testing.T) {
+	ws := newFakeSyncer()
+	core, stop := NewAsyncCore(newSyncerLogger(ws).Core(), 1000, time.Hour)
+	defer stop()
+	logger := zap.New(core)
+	for i := 0; i < 500; i++ {
+		logger.Info(fmt.Sprint(i))
+	}
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(messages(t, ws)); got != 500 {
+		t.Errorf("got %d entries when Sync returned, want 500", got)
+	}
+	if ws.syncCount() != 1 {
+		t.Errorf("inner synced %d times, want 1", ws.syncCount())
+	}
+}
+
+// countingHook is a zapcore.CheckWriteHook that records how many entries ws
+// held when it ran, and then returns instead of exiting.
+type countingHook struct {
+	ws   *fakeSyncer
+	seen *int
+}
+
+func (h countingHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	*h.seen = strings.Count(h.ws.String(), "\n")
+}
+
+func TestNewAsyncCoreFinalEntries(t *testing.T) {
+	tests := []struct {
+		level zapcore.Level
+		// panics reports whether logging at level panics.
+		panics bool
+	}{
+		{zapcore.DPanicLevel, false},
+		{zapcore.PanicLevel, true},
+		{zapcore.FatalLevel, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			ws := newFakeSyncer()
+			core, stop := NewAsyncCore(newSyncerLogger(ws).Core(), 1000, time.Hour)
+			defer stop()
+			seen := -1
+			logger := zap.New(core, zap.WithFatalHook(countingHook{ws: ws, seen: &seen}))
+			for i := 0; i < 10; i++ {
+				logger.Info(fmt.Sprint(i))
+			}
+
+			func() {
+				defer func() {
+					if p := recover(); (p != nil) != tt.panics {
+						t.Errorf("recovered %v, want a panic: %v", p, tt.panics)
+					}
+				}()
+				logger.Log(tt.level, "boom")
+			}()
+
+			msgs := messages(t, ws)
+			if len(msgs) != 11 || msgs[10] != "boom" {
+				t.Fatalf("got entries %q when the log call returned, want the 10 queued ones, then boom", msgs)
+			}
+			if tt.level == zapcore.FatalLevel && seen != 11 {
+				t.Errorf("fatal hook ran with %d entries written, want all 11", seen)
+			}
+			if ws.syncCount() == 0 {
+				t.Error("the inner core was not synced")
+			}
+		})
+	}
+}
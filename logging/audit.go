@@ -0,0 +1,213 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// auditHashPrefix starts the hash field that closes every audit line.
+const auditHashPrefix = `,"hash":"`
+
+// NewAuditLogger returns a logger for an append-only audit trail in the
+// JSON file at path. Every entry carries a "seq" number, one more than the
+// previous entry's, and a "prev_hash" holding the previous entry's "hash",
+// while its own "hash" is the hex SHA-256 of the line up to that field, so a
+// removed, reordered or edited entry breaks the chain. VerifyAuditLog checks
+// a file.
+//
+// An existing file is appended to, continuing the chain from its last entry.
+// The logger is safe for concurrent use.
+func NewAuditLogger(path string) (*zap.Logger, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("logging: open audit log: %w", err)
+	}
+	chain, err := resumeAuditChain(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	core := &auditCore{
+		LevelEnabler: zapcore.DebugLevel,
+		enc:          zapcore.NewJSONEncoder(newOptions().config.EncoderConfig),
+		out:          zapcore.AddSync(f),
+		chain:        chain,
+	}
+	return zap.New(core), nil
+}
+
+// auditChain is the state shared by an audit core and its children.
+type auditChain struct {
+	mu   sync.Mutex
+	seq  uint64
+	hash string
+}
+
+type auditCore struct {
+	zapcore.LevelEnabler
+	enc   zapcore.Encoder
+	out   zapcore.WriteSyncer
+	chain *auditChain
+}
+
+func (c *auditCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return &clone
+}
+
+func (c *auditCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *auditCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.chain.mu.Lock()
+	defer c.chain.mu.Unlock()
+
+	seq := c.chain.seq + 1
+	fields = append(fields[:len(fields):len(fields)],
+		zap.Uint64("seq", seq),
+		zap.String("prev_hash", c.chain.hash),
+	)
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	content := bytes.TrimRight(buf.Bytes(), "\n")
+	hash := auditHash(content)
+	line := make([]byte, 0, len(content)+len(auditHashPrefix)+len(hash)+3)
+	line = append(line, content[:len(content)-1]...) // drop the closing brace
+	line = append(line, auditHashPrefix...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+	if _, err := c.out.Write(line); err != nil {
+		return err
+	}
+
+	c.chain.seq, c.chain.hash = seq, hash
+	return nil
+}
+
+func (c *auditCore) Sync() error {
+	return c.out.Sync()
+}
+
+func auditHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// resumeAuditChain reads the chain state from the last line of f.
+func resumeAuditChain(f *os.File) (*auditChain, error) {
+	line, err := lastLine(f)
+	if err != nil {
+		return nil, fmt.Errorf("logging: read audit log: %w", err)
+	}
+	if len(line) == 0 {
+		return &auditChain{}, nil
+	}
+	var last struct {
+		Seq  uint64 `json:"seq"`
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(line, &last); err != nil {
+		return nil, fmt.Errorf("logging: parse last audit entry: %w", err)
+	}
+	return &auditChain{seq: last.Seq, hash: last.Hash}, nil
+}
+
+// lastLine returns the last non-empty line of f, reading it from the end so
+// that large files are not read in full.
+func lastLine(f *os.File) ([]byte, error) {
+	const chunk = 4096
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	var tail []byte
+	for off := size; off > 0; {
+		n := int64(chunk)
+		if off < n {
+			n = off
+		}
+		off -= n
+		buf := make([]byte, n)
+		if _, err := f.ReadAt(buf, off); err != nil {
+			return nil, err
+		}
+		tail = append(buf, tail...)
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+	}
+	return bytes.TrimRight(tail, "\n"), nil
+}
+
+// VerifyAuditLog checks the hash chain of an audit log written by
+// NewAuditLogger and returns an error describing the first broken entry.
+func VerifyAuditLog(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("logging: open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		seq  uint64
+		hash string
+	)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<26)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Bytes()
+		i := bytes.LastIndex(line, []byte(auditHashPrefix))
+		if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+			return fmt.Errorf("logging: audit line %d has no hash", lineNo)
+		}
+		want := string(line[i+len(auditHashPrefix) : len(line)-2])
+		content := append(line[:i:i], '}')
+		if got := auditHash(content); got != want {
+			return fmt.Errorf("logging: audit line %d: hash mismatch", lineNo)
+		}
+
+		var entry struct {
+			Seq      uint64 `json:"seq"`
+			PrevHash string `json:"prev_hash"`
+		}
+		if err := json.Unmarshal(content, &entry); err != nil {
+			return fmt.Errorf("logging: audit line %d: %w", lineNo, err)
+		}
+		if entry.Seq != seq+1 {
+			return fmt.Errorf("logging: audit line %d: seq %d follows %d", lineNo, entry.Seq, seq)
+		}
+		if entry.PrevHash != hash {
+			return fmt.Errorf("logging: audit line %d: prev_hash does not match the previous entry", lineNo)
+		}
+		seq, hash = entry.Seq, want
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("logging: read audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// writeAuditLog logs n audit events to a new audit log at path, from several
+// goroutines.
+func writeAuditLog(t *testing.T, path string, n int) {
+	t.Helper()
+	logger, err := NewAuditLogger(path)
+	if err != nil {
+		t.Fatalf("NewAuditLogger: %v", err)
+	}
+	user := logger.With(zap.String("user", "frank"))
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			user.Info("permission granted", zap.Int("resource", i))
+		}(i)
+	}
+	wg.Wait()
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewAuditLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeAuditLog(t, path, 10)
+	// A second logger on the same file continues the chain.
+	writeAuditLog(t, path, 5)
+
+	if err := VerifyAuditLog(path); err != nil {
+		t.Errorf("VerifyAuditLog: %v", err)
+	}
+	entries := readEntries(t, path)
+	if len(entries) != 15 {
+		t.Fatalf("got %d entries, want 15", len(entries))
+	}
+	for i, e := range entries {
+		if e["seq"] != float64(i+1) {
+			t.Errorf("entry %d: seq = %v, want %d", i, e["seq"], i+1)
+		}
+		if e["user"] != "frank" {
+			t.Errorf("entry %d: user = %v, want frank", i, e["user"])
+		}
+	}
+	if entries[0]["prev_hash"] != "" {
+		t.Errorf("first prev_hash = %v, want empty", entries[0]["prev_hash"])
+	}
+	if entries[10]["prev_hash"] != entries[9]["hash"] {
+		t.Error("the second logger did not resume the chain from the last hash")
+	}
+}
+
+func TestVerifyAuditLogTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines [][]byte) [][]byte
+	}{
+		{"edited", func(lines [][]byte) [][]byte {
+			lines[1] = bytes.Replace(lines[1], []byte("frank"), []byte("mallory"), 1)
+			return lines
+		}},
+		{"removed", func(lines [][]byte) [][]byte {
+			return append(lines[:1], lines[2:]...)
+		}},
+		{"reordered", func(lines [][]byte) [][]byte {
+			lines[0], lines[1] = lines[1], lines[0]
+			return lines
+		}},
+		{"hash stripped", func(lines [][]byte) [][]byte {
+			i := bytes.LastIndex(lines[2], []byte(auditHashPrefix))
+			lines[2] = append(lines[2][:i:i], '}')
+			return lines
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			writeAuditLog(t, path, 3)
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := tt.tamper(bytes.Split(bytes.TrimRight(b, "\n"), []byte("\n")))
+			if err := os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := VerifyAuditLog(path); err == nil {
+				t.Error("VerifyAuditLog accepted a tampered log")
+			}
+		})
+	}
+}
+
+func TestNewAuditLoggerCorruptTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, []byte("not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewAuditLogger(path); err == nil {
+		t.Error("NewAuditLogger resumed from a last line that is not an audit entry")
+	}
+}
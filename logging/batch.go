@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// batcher is the background loop behind HTTPSyncer and LokiSyncer. It
+// collects queued items and hands them to flush in batches of up to size,
+// whenever the flush interval passes, and on sync. Items of a batch flush
+// fails on are counted as dropped.
+type batcher[T any] struct {
+	size  int
+	flush func(batch []T) error
+	// errClosed is what sync returns once the batcher is closed.
+	errClosed error
+
+	queue   chan T
+	flushes chan chan error
+	done    chan struct{}
+	stopped chan struct{}
+	closed  sync.Once
+	dropped atomic.Int64
+}
+
+// newBatcher starts a batcher whose queue holds queueBatches batches. flush
+// must not keep the slice it is given, which is reused for the next batch.
+func newBatcher[T any](size, queueBatches int, flushInterval time.Duration, errClosed error, flush func(batch []T) error) *batcher[T] {
+	b := &batcher[T]{
+		size:      size,
+		flush:     flush,
+		errClosed: errClosed,
+		queue:     make(chan T, size*queueBatches),
+		flushes:   make(chan chan error),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go b.run(flushInterval)
+	return b
+}
+
+// add queues item without blocking; when the queue is full, it is dropped.
+func (b *batcher[T]) add(item T) {
+	select {
+	case b.queue <- item:
+	default:
+		b.dropped.Add(1)
+	}
+}
+
+// sync flushes every queued item and reports whether that succeeded.
+func (b *batcher[T]) sync() error {
+	reply := make(chan error, 1)
+	select {
+	case b.flushes <- reply:
+		return <-reply
+	case <-b.done:
+		return b.errClosed
+	}
+}
+
+// close flushes the queued items, stops the loop and waits for it to exit.
+// Closing again does nothing.
+func (b *batcher[T]) close() error {
+	err := b.sync()
+	b.closed.Do(func() { close(b.done) })
+	<-b.stopped
+	if errors.Is(err, b.errClosed) {
+		return nil
+	}
+	return err
+}
+
+func (b *batcher[T]) run(flushInterval time.Duration) {
+	defer close(b.stopped)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, b.size)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := b.flush(batch)
+		if err != nil {
+			b.dropped.Add(int64(len(batch)))
+		}
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		select {
+		case item := <-b.queue:
+			batch = append(batch, item)
+			if len(batch) >= b.size {
+				_ = flush()
+			}
+		case <-ticker.C:
+			_ = flush()
+		case reply := <-b.flushes:
+			var err error
+			for drained := false; !drained; {
+				select {
+				case item := <-b.queue:
+					batch = append(batch, item)
+					if len(batch) >= b.size {
+						err = errors.Join(err, flush())
+					}
+				default:
+					drained = true
+				}
+			}
+			reply <- errors.Join(err, flush())
+		case <-b.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingFlush records the batches it is given, failing with err.
+type recordingFlush struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *recordingFlush) flush(batch []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]int(nil), batch...))
+	return r.err
+}
+
+func (r *recordingFlush) got() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.batches
+}
+
+var errTestClosed = errors.New("closed")
+
+func TestBatcher(t *testing.T) {
+	r := &recordingFlush{}
+	b := newBatcher(2, 4, time.Hour, errTestClosed, r.flush)
+	for i := 1; i <= 5; i++ {
+		b.add(i)
+	}
+	if err := b.sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if want := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(r.got(), want) {
+		t.Errorf("batches = %v, want %v", r.got(), want)
+	}
+
+	if err := b.close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+	if err := b.close(); err != nil {
+		t.Errorf("second close: %v", err)
+	}
+	select {
+	case <-b.stopped:
+	default:
+		t.Error("loop still running after close")
+	}
+	if err := b.sync(); !errors.Is(err, errTestClosed) {
+		t.Errorf("sync after close = %v, want errTestClosed", err)
+	}
+}
+
+func TestBatcherFlushInterval(t *testing.T) {
+	r := &recordingFlush{}
+	b := newBatcher(10, 4, 10*time.Millisecond, errTestClosed, r.flush)
+	t.Cleanup(func() { b.close() })
+	b.add(1)
+
+	deadline := time.Now().Add(time.Second)
+	for len(r.got()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if want := [][]int{{1}}; !reflect.DeepEqual(r.got(), want) {
+		t.Errorf("batches = %v, want %v", r.got(), want)
+	}
+}
+
+func TestBatcherFlushError(t *testing.T) {
+	r := &recordingFlush{err: errors.New("rejected")}
+	b := newBatcher(10, 4, time.Hour, errTestClosed, r.flush)
+	b.add(1)
+	b.add(2)
+	if err := b.close(); err == nil {
+		t.Error("close succeeded, want the flush error")
+	}
+	if got := b.dropped.Load(); got != 2 {
+		t.Errorf("dropped = %d, want 2", got)
+	}
+}
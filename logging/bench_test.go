@@ -0,0 +1,229 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The benchmarks measure the cost of different ways of logging with zap. Run
+// them with:
+//
+//	go test -run '^$' -bench . -benchmem ./logging
+
+// benchUser is an example of a struct logged without reflection: its
+// MarshalLogObject writes every field with its concrete type.
+type benchUser struct {
+	Name      string
+	Email     string
+	Age       int
+	CreatedAt time.Time
+}
+
+func (u benchUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.Name)
+	enc.AddString("email", u.Email)
+	enc.AddInt("age", u.Age)
+	enc.AddTime("created_at", u.CreatedAt)
+	return nil
+}
+
+// plainBenchUser has benchUser's fields but not its MarshalLogObject method,
+// so zap.Any has to encode it with reflection.
+type plainBenchUser benchUser
+
+var user = benchUser{
+	Name:      "marmotedu",
+	Email:     "marmotedu@example.com",
+	Age:       30,
+	CreatedAt: time.Date(2023, 10, 24, 11, 6, 18, 0, time.UTC),
+}
+
+// newDiscardLogger returns a production-encoded logger that throws its output
+// away, so that the benchmarks measure zap rather than I/O.
+func newDiscardLogger() *zap.Logger {
+	return newDiscardLoggerWith(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()))
+}
+
+func newDiscardLoggerWith(enc zapcore.Encoder) *zap.Logger {
+	// io.Discard's Write does nothing, and the core does not sync per entry,
+	// so the sink adds no measurable cost.
+	return zap.New(zapcore.NewCore(enc, zapcore.AddSync(io.Discard), zapcore.DebugLevel))
+}
+
+func BenchmarkAnyUser(b *testing.B) {
+	logger := newDiscardLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("signed up", zap.Any("user", plainBenchUser(user)))
+	}
+}
+
+func BenchmarkObjectUser(b *testing.B) {
+	logger := newDiscardLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("signed up", Object("user", user))
+	}
+}
+
+// expensiveFields stands for debug-only data that is costly to put together.
+func expensiveFields() []zap.Field {
+	return []zap.Field{zap.String("dump", fmt.Sprintf("%#v", user))}
+}
+
+func BenchmarkDroppedEager(b *testing.B) {
+	logger := newDiscardLogger().WithOptions(zap.IncreaseLevel(zapcore.InfoLevel))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Debug("request", expensiveFields()...)
+	}
+}
+
+func BenchmarkDroppedLazy(b *testing.B) {
+	logger := newDiscardLogger().WithOptions(zap.IncreaseLevel(zapcore.InfoLevel))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Debug("request", LazyFunc(expensiveFields))
+	}
+}
+
+// The demo's claim that SugaredLogger is about 50% slower than Logger is
+// checked by the next benchmarks, which all log the demo's url, attempt and
+// backoff fields.
+const benchURL = "http://marmotedu.com"
+
+func BenchmarkLogger(b *testing.B) {
+	logger := newDiscardLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("failed to fetch URL",
+			zap.String("url", benchURL),
+			zap.Int("attempt", 3),
+			zap.Duration("backoff", time.Second),
+		)
+	}
+}
+
+func BenchmarkLogfmtLogger(b *testing.B) {
+	logger := newDiscardLoggerWith(NewLogfmtEncoder(zap.NewProductionEncoderConfig()))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("failed to fetch URL",
+			zap.String("url", benchURL),
+			zap.Int("attempt", 3),
+			zap.Duration("backoff", time.Second),
+		)
+	}
+}
+
+func BenchmarkSugaredInfow(b *testing.B) {
+	sugar := newDiscardLogger().Sugar()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sugar.Infow("failed to fetch URL",
+			"url", benchURL,
+			"attempt", 3,
+			"backoff", time.Second,
+		)
+	}
+}
+
+func BenchmarkSugaredInfof(b *testing.B) {
+	sugar := newDiscardLogger().Sugar()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sugar.Infof("failed to fetch URL: %s, attempt %d, backoff %v", benchURL, 3, time.Second)
+	}
+}
+
+// benchField keeps the benchmarked fields alive so they are not optimized away.
+var benchField zap.Field
+
+// BenchmarkAnyInt and BenchmarkFastInt compare the dispatch of zap.Any and
+// Fast. Neither allocates for an int, so only ns/op differs.
+func BenchmarkAnyInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchField = zap.Any("attempt", i)
+	}
+}
+
+func BenchmarkFastInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchField = Fast("attempt", i)
+	}
+}
+
+// discardOutput replaces the core of a logger built by this package
+// with one writing JSON to io.Discard, so that loggers built by different
+// constructors can be compared on what they do before encoding. The
+// replacement also leaves out their sampling, which would drop most of the
+// benchmark's identical entries.
+var discardOutput = WithZapOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+	return newDiscardLogger().Core()
+}))
+
+// The next benchmarks measure what NewFastLogger saves: the runtime.Caller
+// lookup on every entry, and at ErrorLevel the stacktrace as well.
+
+func BenchmarkProductionLogger(b *testing.B) {
+	logger, err := NewLogger(discardOutput)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkInfo(b, logger)
+}
+
+func BenchmarkFastLogger(b *testing.B) {
+	logger, err := NewFastLogger(discardOutput)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkInfo(b, logger)
+}
+
+func BenchmarkProductionLoggerError(b *testing.B) {
+	logger, err := NewLogger(discardOutput)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkError(b, logger)
+}
+
+func BenchmarkFastLoggerError(b *testing.B) {
+	logger, err := NewFastLogger(discardOutput)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkError(b, logger)
+}
+
+func benchmarkInfo(b *testing.B, logger *zap.Logger) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("failed to fetch URL", zap.String("url", benchURL), zap.Int("attempt", 3))
+	}
+}
+
+func benchmarkError(b *testing.B, logger *zap.Logger) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Error("failed to fetch URL", zap.String("url", benchURL), zap.Int("attempt", 3))
+	}
+}
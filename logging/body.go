@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// BodyLoggingMiddleware logs the request and response bodies of JSON
+// requests, for debugging an API. Each request gets one InfoLevel entry with
+// "request_body" and "response_body", the first maxBytes of each followed by
+// "...(truncated)" when there was more. A body is only captured when its
+// Content-Type is application/json or a +json type; other bodies, binary ones
+// in particular, are left out, and a request with neither is not logged.
+//
+// The handler still reads the complete, unmodified request body: only the
+// captured prefix is held in memory, the rest streams from the client.
+func BodyLoggingMiddleware(l *zap.Logger, maxBytes int) func(http.Handler) http.Handler {
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody []byte
+			reqJSON := r.Body != nil && r.Body != http.NoBody && isJSON(r.Header.Get("Content-Type"))
+			if reqJSON {
+				// Read one byte more than is logged to know whether to
+				// mark the body as truncated.
+				prefix, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBytes)+1))
+				r.Body = prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), r.Body), Closer: r.Body}
+				if err != nil {
+					reqJSON = false
+				}
+				reqBody = prefix
+			}
+
+			rec := &bodyRecorder{ResponseWriter: w, max: maxBytes}
+			next.ServeHTTP(rec, r)
+
+			if !reqJSON && !rec.json {
+				return
+			}
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+			}
+			if reqJSON {
+				fields = append(fields, zap.String("request_body", truncateBody(reqBody, maxBytes)))
+			}
+			if rec.json {
+				fields = append(fields, zap.String("response_body", truncateBody(rec.body.Bytes(), maxBytes)))
+			}
+			l.Info("http body", fields...)
+		})
+	}
+}
+
+// prefixedBody is a request body whose start has already been read into
+// memory.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyRecorder keeps the first max bytes of a JSON response body, plus one
+// to tell whether there was more.
+type bodyRecorder struct {
+	http.ResponseWriter
+	max     int
+	checked bool
+	json    bool
+	body    bytes.Buffer
+}
+
+func (r *bodyRecorder) WriteHeader(status int) {
+	r.check()
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.check()
+	if r.json {
+		if room := r.max + 1 - r.body.Len(); room > 0 {
+			if room > len(b) {
+				room = len(b)
+			}
+			r.body.Write(b[:room])
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered response to the client, for streaming handlers.
+// It does nothing if the wrapped writer cannot flush.
+func (r *bodyRecorder) Flush() {
+	r.check()
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack hands the connection over to the handler, as websocket upgrades
+// need, and fails if the wrapped writer does not support it. What is written
+// to a hijacked connection is not captured.
+func (r *bodyRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the wrapped writer, so that http.ResponseController reaches
+// the features bodyRecorder does not forward itself.
+func (r *bodyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// check looks at the Content-Type once the handler commits to the headers.
+func (r *bodyRecorder) check() {
+	if !r.checked {
+		r.checked = true
+		r.json = isJSON(r.Header().Get("Content-Type"))
+	}
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// truncateBody cuts b down to max bytes, between runes, like
+// WithMaxFieldLength does.
+func truncateBody(b []byte, max int) string {
+	if len(b) <= max {
+		return string(b)
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(b[cut]) {
+		cut--
+	}
+	return string(b[:cut]) + truncatedMarker
+}
@@ -0,0 +1,173 @@
+package logging
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestBodyLoggingMiddleware(t *testing.T) {
+	const reqBody = `{"user":"frank","items":[1,2,3]}`
+	tests := []struct {
+		name        string
+		reqType     string
+		respType    string
+		maxBytes    int
+		wantLogged  bool
+		wantReq     interface{}
+		wantResp    interface{}
+		wantNoField string
+	}{
+		{
+			name:       "json both ways",
+			reqType:    "application/json",
+			respType:   "application/json; charset=utf-8",
+			maxBytes:   1024,
+			wantLogged: true,
+			wantReq:    reqBody,
+			wantResp:   `{"ok":true}`,
+		},
+		{
+			name:       "truncated",
+			reqType:    "application/json",
+			respType:   "application/problem+json",
+			maxBytes:   8,
+			wantLogged: true,
+			wantReq:    `{"user":` + truncatedMarker,
+			wantResp:   `{"ok":tr` + truncatedMarker,
+		},
+		{
+			name:        "binary response",
+			reqType:     "application/json",
+			respType:    "application/octet-stream",
+			maxBytes:    1024,
+			wantLogged:  true,
+			wantReq:     reqBody,
+			wantNoField: "response_body",
+		},
+		{
+			name:        "binary request",
+			reqType:     "application/octet-stream",
+			respType:    "application/json",
+			maxBytes:    1024,
+			wantLogged:  true,
+			wantResp:    `{"ok":true}`,
+			wantNoField: "request_body",
+		},
+		{
+			name:     "no json",
+			reqType:  "text/plain",
+			respType: "image/png",
+			maxBytes: 1024,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := logtest.NewTestLogger()
+			var received string
+			h := BodyLoggingMiddleware(logger, tt.maxBytes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("reading the request body: %v", err)
+				}
+				received = string(b)
+				w.Header().Set("Content-Type", tt.respType)
+				w.Write([]byte(`{"ok":true}`))
+			}))
+			r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(reqBody))
+			r.Header.Set("Content-Type", tt.reqType)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if received != reqBody {
+				t.Errorf("handler read %q, want the full body %q", received, reqBody)
+			}
+			if w.Body.String() != `{"ok":true}` {
+				t.Errorf("client got %q, want the full response", w.Body)
+			}
+			if !tt.wantLogged {
+				if logs.Len() != 0 {
+					t.Errorf("got %d entries, want none", logs.Len())
+				}
+				return
+			}
+			entry := logs.All()[0]
+			want := map[string]interface{}{
+				"method": http.MethodPost,
+				"path":   "/orders",
+			}
+			if tt.wantReq != nil {
+				want["request_body"] = tt.wantReq
+			}
+			if tt.wantResp != nil {
+				want["response_body"] = tt.wantResp
+			}
+			for key, v := range want {
+				if got, _ := logtest.FieldValue(entry, key); got != v {
+					t.Errorf("%s = %v, want %v", key, got, v)
+				}
+			}
+			if tt.wantNoField != "" {
+				if got, ok := logtest.FieldValue(entry, tt.wantNoField); ok {
+					t.Errorf("%s = %v, want it left out", tt.wantNoField, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBodyRecorderForwarding(t *testing.T) {
+	tests := []struct {
+		name string
+		use  func(t *testing.T, w http.ResponseWriter)
+		// flushed reports whether the underlying recorder was flushed.
+		flushed bool
+	}{
+		{
+			name: "flush",
+			use: func(t *testing.T, w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "application/json")
+				w.(http.Flusher).Flush()
+			},
+			flushed: true,
+		},
+		{
+			name: "hijack unsupported",
+			use: func(t *testing.T, w http.ResponseWriter) {
+				if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+					t.Error("Hijack succeeded on a writer that cannot hijack")
+				}
+			},
+		},
+		{
+			name: "unwrap",
+			use: func(t *testing.T, w http.ResponseWriter) {
+				u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+				if !ok {
+					t.Fatal("writer has no Unwrap method")
+				}
+				if _, ok := u.Unwrap().(*httptest.ResponseRecorder); !ok {
+					t.Errorf("Unwrap returned %T, want the wrapped recorder", u.Unwrap())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := logtest.NewTestLogger()
+			h := BodyLoggingMiddleware(logger, 1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.use(t, w)
+			}))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+			if rec.Flushed != tt.flushed {
+				t.Errorf("Flushed = %v, want %v", rec.Flushed, tt.flushed)
+			}
+		})
+	}
+}
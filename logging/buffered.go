@@ -0,0 +1,21 @@
+package logging
+
+import "time"
+
+type bufferConfig struct {
+	size          int
+	flushInterval time.Duration
+}
+
+// WithBufferedOutput buffers the encoded entries, up to size bytes, instead
+// of writing each one to the outputs straight away, saving a system call per
+// entry. The buffer is written out when it is full, every flushInterval, and
+// on Sync, so call Sync before the program exits to keep the last entries.
+// Zero values stand for zap's defaults of 256 kB and 30 seconds. Close the
+// logger with Close to write out the buffer and stop the goroutine doing the
+// periodic flushes.
+func WithBufferedOutput(size int, flushInterval time.Duration) Option {
+	return func(o *options) {
+		o.buffer = &bufferConfig{size: size, flushInterval: flushInterval}
+	}
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWithBufferedOutput(t *testing.T) {
+	tests := []struct {
+		name          string
+		size          int
+		flushInterval time.Duration
+		// wantUnsynced is how many of the 5 entries reach the file without
+		// a Sync.
+		wantUnsynced int
+	}{
+		{"held until Sync", 0, time.Hour, 0},
+		{"flushed periodically", 0, 10 * time.Millisecond, 5},
+		// Each entry is well over 128 bytes, so each one written out the
+		// entry buffered before it; the last is left for Sync.
+		{"flushed when full", 128, time.Hour, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.log")
+			logger, err := NewLogger(WithOutputPaths(path), WithBufferedOutput(tt.size, tt.flushInterval))
+			if err != nil {
+				t.Fatalf("NewLogger: %v", err)
+			}
+			for i := 0; i < 5; i++ {
+				logger.Info(fmt.Sprint("entry ", i))
+			}
+
+			deadline := time.Now().Add(time.Second)
+			for len(readEntries(t, path)) < tt.wantUnsynced && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if got := len(readEntries(t, path)); got != tt.wantUnsynced {
+				t.Errorf("got %d entries before Sync, want %d", got, tt.wantUnsynced)
+			}
+
+			if err := logger.Sync(); err != nil {
+				t.Fatalf("Sync: %v", err)
+			}
+			entries := readEntries(t, path)
+			if len(entries) != 5 {
+				t.Fatalf("got %d entries after Sync, want all 5", len(entries))
+			}
+			for i, e := range entries {
+				if want := fmt.Sprint("entry ", i); e["msg"] != want {
+					t.Errorf("entry %d has msg %v, want %q", i, e["msg"], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithBufferedOutputClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+	path := filepath.Join(t.TempDir(), "out.log")
+	logger, err := NewLogger(WithOutputPaths(path), WithBufferedOutput(0, time.Hour))
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		logger.Info(fmt.Sprint("entry ", i))
+	}
+
+	if err := Close(logger); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := len(readEntries(t, path)); got != 5 {
+		t.Errorf("got %d entries after Close, want all 5", got)
+	}
+	// The flush goroutine is started by the first write and must be gone.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines after Close, want %d", after, before)
+	}
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"math"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// ByteSize logs a number of bytes as a string in binary units with one
+// decimal, such as "1.0 MiB" for 1048576, which is easier to read than the
+// raw number. Sizes under 1024 stay in bytes, "1023 B", and a size that
+// would round up to 1024 of a unit is written in the next one, so 1048575
+// is "1.0 MiB" rather than "1024.0 KiB". Zero is "0 B" and negative sizes,
+// like a shrinking file's delta, get a minus sign: "-1.5 KiB".
+//
+// Use ByteSizeWithRaw to keep the exact number as well.
+func ByteSize(key string, bytes int64) zap.Field {
+	return zap.String(key, formatByteSize(bytes))
+}
+
+// ByteSizeWithRaw returns ByteSize(key, bytes) followed by the exact number
+// under key+"_bytes", for queries that compare sizes.
+func ByteSizeWithRaw(key string, bytes int64) []zap.Field {
+	return []zap.Field{ByteSize(key, bytes), zap.Int64(key+"_bytes", bytes)}
+}
+
+func formatByteSize(bytes int64) string {
+	sign := ""
+	// Converted before negating, so that math.MinInt64 does not overflow.
+	n := uint64(bytes)
+	if bytes < 0 {
+		sign = "-"
+		n = -n
+	}
+	if n < 1024 {
+		return sign + strconv.FormatUint(n, 10) + " B"
+	}
+
+	v := float64(n) / 1024
+	unit := 0
+	// Compare the value as it will be printed, which is what decides
+	// whether it reaches the next unit.
+	for unit < len(byteUnits)-1 && math.Round(v*10)/10 >= 1024 {
+		v /= 1024
+		unit++
+	}
+	return sign + strconv.FormatFloat(v, 'f', 1, 64) + " " + byteUnits[unit]
+}
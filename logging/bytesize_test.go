@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1, "1 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{1048575, "1.0 MiB"},
+		{1048576, "1.0 MiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{1<<30 - 1, "1.0 GiB"},
+		{1 << 30, "1.0 GiB"},
+		{3<<30 + 1<<29, "3.5 GiB"},
+		{1 << 40, "1.0 TiB"},
+		{-1, "-1 B"},
+		{-1536, "-1.5 KiB"},
+		{math.MaxInt64, "8.0 EiB"},
+		{math.MinInt64, "-8.0 EiB"},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.FormatInt(tt.bytes, 10), func(t *testing.T) {
+			if got := ByteSize("size", tt.bytes); !got.Equals(zap.String("size", tt.want)) {
+				t.Errorf("ByteSize(%d) = %q, want %q", tt.bytes, got.String, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteSizeWithRaw(t *testing.T) {
+	fields := ByteSizeWithRaw("size", 1048576)
+	want := []zap.Field{zap.String("size", "1.0 MiB"), zap.Int64("size_bytes", 1048576)}
+	if len(fields) != len(want) {
+		t.Fatalf("got %d fields, want %d", len(fields), len(want))
+	}
+	for i := range want {
+		if !fields[i].Equals(want[i]) {
+			t.Errorf("field %d = %+v, want %+v", i, fields[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithFunctionCaller turns caller reporting on and appends the calling
+// function to it, as in "uber-zap-demo/demo-1.go:30 (main.main)". It can be
+// combined with WithShortCaller.
+func WithFunctionCaller() Option {
+	return func(o *options) {
+		o.config.DisableCaller = false
+		o.functionCaller = true
+	}
+}
+
+// WithShortCaller turns caller reporting on and cuts it down to the file's
+// base name and the line, as in "demo-1.go:30". It can be combined with
+// WithFunctionCaller.
+func WithShortCaller() Option {
+	return func(o *options) {
+		o.config.DisableCaller = false
+		o.shortCaller = true
+	}
+}
+
+// callerEncoder returns the encoder for the given WithShortCaller and
+// WithFunctionCaller settings.
+func callerEncoder(short, function bool) zapcore.CallerEncoder {
+	switch {
+	case short && function:
+		return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+			if fn := callerFunction(caller); fn != "" {
+				enc.AppendString(shortCallerPath(caller) + " (" + fn + ")")
+				return
+			}
+			enc.AppendString(shortCallerPath(caller))
+		}
+	case short:
+		return ShortFileCallerEncoder
+	default:
+		return FunctionCallerEncoder
+	}
+}
+
+// ShortFileCallerEncoder encodes the caller as its file's base name and the
+// line, without the directory zapcore.ShortCallerEncoder keeps.
+func ShortFileCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(shortCallerPath(caller))
+}
+
+func shortCallerPath(caller zapcore.EntryCaller) string {
+	if !caller.Defined {
+		return "undefined"
+	}
+	return filepath.Base(caller.File) + ":" + strconv.Itoa(caller.Line)
+}
+
+// FunctionCallerEncoder encodes the caller as zapcore.ShortCallerEncoder does,
+// followed by the package-qualified function name in parentheses. zap only
+// calls it for entries with caller information, so the function lookup costs
+// nothing when callers are disabled.
+func FunctionCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	fn := callerFunction(caller)
+	if fn == "" {
+		enc.AppendString(caller.TrimmedPath())
+		return
+	}
+	enc.AppendString(caller.TrimmedPath() + " (" + fn + ")")
+}
+
+// callerFunction returns the caller's function as pkg.Func, or "" if it is
+// unknown.
+func callerFunction(caller zapcore.EntryCaller) string {
+	fn := caller.Function
+	if fn == "" {
+		if f := runtime.FuncForPC(caller.PC); f != nil {
+			fn = f.Name()
+		}
+	}
+	// Keep only the last element of the import path: pkg.Func.
+	if i := strings.LastIndexByte(fn, '/'); i >= 0 {
+		fn = fn[i+1:]
+	}
+	return fn
+}
+
+// helperLogger returns l for use by an exported helper that logs on its
+// caller's behalf, such as LogError or Notice: every such helper logs through
+// it, directly from the helper's own body, so that the caller field points at
+// the code calling the helper rather than at the helper.
+func helperLogger(l *zap.Logger) *zap.Logger {
+	return l.WithOptions(zap.AddCallerSkip(1))
+}
@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithFunctionCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want *regexp.Regexp
+	}{
+		{
+			name: "function",
+			opts: []Option{WithFunctionCaller()},
+			want: regexp.MustCompile(`^logging/caller_test\.go:\d+ \(logging\.TestWithFunctionCaller\.func1\)$`),
+		},
+		{
+			name: "short and function",
+			opts: []Option{WithShortCaller(), WithFunctionCaller()},
+			want: regexp.MustCompile(`^caller_test\.go:\d+ \(logging\.TestWithFunctionCaller\.func1\)$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, tt.opts...)
+			logger.Info("fetched")
+
+			caller, _ := entries()[0]["caller"].(string)
+			if !tt.want.MatchString(caller) {
+				t.Errorf("caller = %q, want it to match %s", caller, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFunctionCallerDisabled(t *testing.T) {
+	logger, entries := newFileLogger(t, WithFunctionCaller(), WithZapOptions(zap.WithCaller(false)))
+	logger.Info("fetched")
+
+	if caller, ok := entries()[0]["caller"]; ok {
+		t.Errorf("caller = %v, want none with callers turned off", caller)
+	}
+}
+
+func TestWithShortCaller(t *testing.T) {
+	logger, entries := newFileLogger(t, WithSampling(0, 0), WithShortCaller())
+	logger.Info("fetched")
+	want := here(-1)
+	logger.Sugar().Infow("fetched again")
+	wantSugared := here(-1)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"logger", want},
+		{"sugared logger", wantSugared},
+	}
+	got := entries()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller, _ := got[i]["caller"].(string)
+			if strings.Contains(caller, "/") {
+				t.Errorf("caller = %q, want no directory", caller)
+			}
+			if caller != tt.want {
+				t.Errorf("caller = %q, want %q", caller, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortFileCallerEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller zapcore.EntryCaller
+		want   string
+	}{
+		{"nested", zapcore.EntryCaller{Defined: true, File: "/src/uber-zap-demo/demo-1.go", Line: 30}, "demo-1.go:30"},
+		{"bare", zapcore.EntryCaller{Defined: true, File: "main.go", Line: 7}, "main.go:7"},
+		{"undefined", zapcore.EntryCaller{}, "undefined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			enc.AddArray("caller", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+				ShortFileCallerEncoder(tt.caller, arr)
+				return nil
+			}))
+			if got := enc.Fields["caller"].([]interface{})[0]; got != tt.want {
+				t.Errorf("caller = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// logError stands for the user code calling a helper: it logs through
+// LogError and returns where it did so.
+func logError(l *zap.Logger) string {
+	LogError(l, "saving order", errors.New("disk full"))
+	return here(-1)
+}
+
+func TestHelperCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		// log calls the helper and returns the file:line of the call.
+		log func(l *zap.Logger) string
+	}{
+		{"LogError", logError},
+		{"Notice", func(l *zap.Logger) string {
+			Notice(l, "config reloaded")
+			return here(-1)
+		}},
+		{"Scope", func(l *zap.Logger) string {
+			s := NewScope(l)
+			s.Add(zap.Int("items", 3))
+			s.Flush(zapcore.InfoLevel, "request done")
+			return here(-1)
+		}},
+		{"LogStartupConfig", func(l *zap.Logger) string {
+			LogStartupConfig(l, zap.NewProductionConfig())
+			return here(-1)
+		}},
+		{"Templatef", func(l *zap.Logger) string {
+			Templatef(l, zapcore.InfoLevel, "bought %d items", 3)
+			return here(-1)
+		}},
+		{"Go", func(l *zap.Logger) string {
+			Go(l, func() { panic("boom") })
+			return here(-1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			want := tt.log(zap.New(core, zap.AddCaller()))
+			waitForEntries(t, logs, 1)
+
+			c := logs.All()[0].Caller
+			if got := fmt.Sprintf("%s:%d", filepath.Base(c.File), c.Line); got != want {
+				t.Errorf("caller = %s (%s), want the helper's caller at %s", got, c.Function, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// accessMarkerKey is the boolean field marking an entry as an access log.
+const accessMarkerKey = "access"
+
+// clfTimeLayout is the timestamp format of Common Log Format.
+const clfTimeLayout = "[02/Jan/2006:15:04:05 -0700]"
+
+var clfPool = buffer.NewPool()
+
+// NewCLFEncoder returns an encoder that renders the request entries of
+// LoggingMiddleware in Apache Common Log Format:
+//
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.0" 200 2326
+//
+// It reads the remote_addr, user, method, request_uri (or path), proto,
+// status and bytes fields and ignores every other field as well as the
+// message. Missing values are written as "-".
+func NewCLFEncoder() zapcore.Encoder {
+	return &clfEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder()}
+}
+
+// WithCLFAccessLog writes the entries LoggingMiddleware tags with
+// WithAccessLog to out in Common Log Format, instead of to the logger's
+// regular output. All other entries are unaffected.
+func WithCLFAccessLog(out zapcore.WriteSyncer) Option {
+	return func(o *options) {
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &accessCore{
+				Core:   core,
+				access: zapcore.NewCore(NewCLFEncoder(), out, zapcore.DebugLevel),
+			}
+		})
+	}
+}
+
+type clfEncoder struct {
+	*zapcore.MapObjectEncoder
+}
+
+func (enc *clfEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for k, v := range enc.Fields {
+		clone.Fields[k] = v
+	}
+	return &clfEncoder{MapObjectEncoder: clone}
+}
+
+func (enc *clfEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := enc.Clone().(*clfEncoder)
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+
+	host := final.value("remote_addr")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	uri := final.value("request_uri")
+	if uri == "-" {
+		uri = final.value("path")
+	}
+	size := final.value("bytes")
+	if size == "0" {
+		size = "-"
+	}
+
+	buf := clfPool.Get()
+	fmt.Fprintf(buf, "%s - %s %s \"%s %s %s\" %s %s\n",
+		host,
+		final.value("user"),
+		ent.Time.Format(clfTimeLayout),
+		final.value("method"),
+		uri,
+		final.value("proto"),
+		final.value("status"),
+		size,
+	)
+	return buf, nil
+}
+
+// value returns the field named key formatted for a log line, or "-".
+func (enc *clfEncoder) value(key string) string {
+	switch v := enc.Fields[key].(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// accessCore sends entries carrying the access marker to the access core and
+// all others to the wrapped core.
+type accessCore struct {
+	zapcore.Core
+	access zapcore.Core
+}
+
+func (c *accessCore) With(fields []zapcore.Field) zapcore.Core {
+	return &accessCore{Core: c.Core.With(fields), access: c.access.With(fields)}
+}
+
+func (c *accessCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *accessCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	for _, f := range fields {
+		if f.Key == accessMarkerKey && f.Type == zapcore.BoolType && f.Integer == 1 {
+			return c.access.Write(ent, fields)
+		}
+	}
+	return c.Core.Write(ent, fields)
+}
+
+func (c *accessCore) Sync() error {
+	err := c.Core.Sync()
+	if accessErr := c.access.Sync(); err == nil {
+		err = accessErr
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// clfLine matches a Common Log Format line.
+var clfLine = regexp.MustCompile(`^\S+ - \S+ \[\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "[^"]*" \d{3} (\d+|-)\n$`)
+
+func TestWithCLFAccessLog(t *testing.T) {
+	access := newFakeSyncer()
+	logger, entries := newFileLogger(t, WithCLFAccessLog(access))
+	h := LoggingMiddleware(logger, WithAccessLog())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.Info("handling")
+		w.Write([]byte("hello"))
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/index.html?lang=en", nil)
+	r.RemoteAddr = "127.0.0.1:51234"
+	r.SetBasicAuth("frank", "secret")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	line := access.String()
+	if !clfLine.MatchString(line) {
+		t.Errorf("access log %q is not a CLF line", line)
+	}
+	want := regexp.MustCompile(`^127\.0\.0\.1 - frank \[.*\] "GET /index.html\?lang=en HTTP/1\.1" 200 5\n$`)
+	if !want.MatchString(line) {
+		t.Errorf("access log %q does not match %s", line, want)
+	}
+	if got := entries(); len(got) != 1 || got[0]["msg"] != "handling" {
+		t.Errorf("got JSON entries %v, want only the application entry", got)
+	}
+}
+
+func TestCLFEncoder(t *testing.T) {
+	ts := time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*60*60))
+	tests := []struct {
+		name   string
+		fields []zap.Field
+		want   string
+	}{
+		{
+			name: "request",
+			fields: []zap.Field{
+				zap.String("remote_addr", "127.0.0.1:51234"),
+				zap.String("user", "frank"),
+				zap.String("method", http.MethodGet),
+				zap.String("request_uri", "/index.html"),
+				zap.String("proto", "HTTP/1.0"),
+				zap.Int("status", 200),
+				zap.Int64("bytes", 2326),
+			},
+			want: `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.0" 200 2326` + "\n",
+		},
+		{
+			name: "path without body",
+			fields: []zap.Field{
+				zap.String("method", http.MethodHead),
+				zap.String("path", "/"),
+				zap.String("proto", "HTTP/1.1"),
+				zap.Int("status", 204),
+				zap.Int64("bytes", 0),
+			},
+			want: `- - - [10/Oct/2000:13:55:36 -0700] "HEAD / HTTP/1.1" 204 -` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := NewCLFEncoder().EncodeEntry(zapcore.Entry{Time: ts, Message: "http request"}, tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// builtCore is the outermost core of the loggers Build returns. It writes
+// through to the core beneath it and carries what Close releases and what
+// EffectiveConfig reports.
+type builtCore struct {
+	zapcore.Core
+	*built
+}
+
+// built is what a logger from Build shares with the loggers derived from it.
+type built struct {
+	// closers run, last added first, when the logger is closed.
+	closers []func() error
+	closed  sync.Once
+	// config and level are what EffectiveConfig reports.
+	config LogConfigSnapshot
+	level  zap.AtomicLevel
+}
+
+func (c *builtCore) With(fields []zapcore.Field) zapcore.Core {
+	return &builtCore{Core: c.Core.With(fields), built: c.built}
+}
+
+// Close syncs l and releases what Build set up for it: the goroutine of
+// WithBufferedOutput, and the files behind WithOutputPaths when the paths are
+// opened by this package because output is buffered or flattened. l and the loggers derived from it must
+// not be used afterwards. Closing a logger again does nothing.
+//
+// For a logger that Build did not return, or whose core was replaced with
+// zap.WrapCore since, Close only syncs.
+func Close(l *zap.Logger) error {
+	c, ok := l.Core().(*builtCore)
+	if !ok {
+		return l.Sync()
+	}
+	var err error
+	c.closed.Do(func() {
+		err = l.Sync()
+		for i := len(c.closers) - 1; i >= 0; i-- {
+			err = multierr.Append(err, c.closers[i]())
+		}
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// closeSink is the sink zap opens for a "closetest://name" output path.
+type closeSink struct {
+	*fakeSyncer
+	closes int
+}
+
+func (s *closeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closes++
+	return nil
+}
+
+func (s *closeSink) closeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closes
+}
+
+var (
+	closeSinksMu sync.Mutex
+	closeSinks   = map[string]*closeSink{}
+)
+
+func init() {
+	err := zap.RegisterSink("closetest", func(u *url.URL) (zap.Sink, error) {
+		s := &closeSink{fakeSyncer: newFakeSyncer()}
+		closeSinksMu.Lock()
+		closeSinks[u.Host] = s
+		closeSinksMu.Unlock()
+		return s, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// openedSink returns the sink opened for "closetest://name".
+func openedSink(t *testing.T, name string) *closeSink {
+	t.Helper()
+	closeSinksMu.Lock()
+	defer closeSinksMu.Unlock()
+	s, ok := closeSinks[name]
+	if !ok {
+		t.Fatalf("no sink was opened for closetest://%s", name)
+	}
+	return s
+}
+
+func TestClose(t *testing.T) {
+	l, err := NewLogger(WithOutputPaths("closetest://buffered"), WithBufferedOutput(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("before close")
+	sink := openedSink(t, "buffered")
+
+	if err := Close(l); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !strings.Contains(sink.String(), `"msg":"before close"`) {
+		t.Errorf("output = %q, want the entry logged before Close", sink.String())
+	}
+	if got := sink.closeCount(); got != 1 {
+		t.Errorf("sink closed %d times, want 1", got)
+	}
+
+	if err := Close(l.With(zap.String("k", "v"))); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if got := sink.closeCount(); got != 1 {
+		t.Errorf("sink closed %d times after a second Close, want 1", got)
+	}
+}
+
+func TestCloseForeignLogger(t *testing.T) {
+	ws := newFakeSyncer()
+	if err := Close(newSyncerLogger(ws)); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := ws.syncCount(); got != 1 {
+		t.Errorf("syncs = %d, want 1", got)
+	}
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewCloudWatchEncoderConfig returns an encoder config for CloudWatch Logs:
+// flat JSON with "timestamp" as integer milliseconds since the epoch, which
+// CloudWatch handles natively, plus "level" and "message". Use it with a JSON
+// encoder.
+func NewCloudWatchEncoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.TimeKey = "timestamp"
+	cfg.LevelKey = "level"
+	cfg.MessageKey = "message"
+	cfg.EncodeTime = EpochMillisIntTimeEncoder
+	return cfg
+}
+
+// EpochMillisIntTimeEncoder encodes t as an integer number of milliseconds
+// since the Unix epoch. zapcore.EpochMillisTimeEncoder writes a float.
+func EpochMillisIntTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendInt64(t.UnixMilli())
+}
+
+// EMF embeds metrics in the entry using CloudWatch's Embedded Metric Format,
+// so that CloudWatch extracts them as metrics in namespace when it ingests the
+// log line. The metric values are added at the top level of the entry next
+// to an "_aws" object describing them:
+//
+//	{"_aws":{"Timestamp":1698116778000,"CloudWatchMetrics":[{"Namespace":"demo",
+//	"Dimensions":[[]],"Metrics":[{"Name":"latency_ms"}]}]},"latency_ms":12.5}
+func EMF(namespace string, metrics map[string]float64) zap.Field {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return zap.Inline(emf{
+		timestamp: time.Now().UnixMilli(),
+		namespace: namespace,
+		names:     names,
+		metrics:   metrics,
+	})
+}
+
+type emf struct {
+	timestamp int64
+	namespace string
+	names     []string
+	metrics   map[string]float64
+}
+
+func (m emf) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	err := enc.AddObject("_aws", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddInt64("Timestamp", m.timestamp)
+		return enc.AddArray("CloudWatchMetrics", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+			return arr.AppendObject(zapcore.ObjectMarshalerFunc(m.marshalDirective))
+		}))
+	}))
+	for _, name := range m.names {
+		enc.AddFloat64(name, m.metrics[name])
+	}
+	return err
+}
+
+func (m emf) marshalDirective(enc zapcore.ObjectEncoder) error {
+	enc.AddString("Namespace", m.namespace)
+	// A single empty dimension set aggregates the metrics without dimensions.
+	if err := enc.AddArray("Dimensions", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		return arr.AppendArray(zapcore.ArrayMarshalerFunc(func(zapcore.ArrayEncoder) error { return nil }))
+	})); err != nil {
+		return err
+	}
+	return enc.AddArray("Metrics", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, name := range m.names {
+			name := name
+			if err := arr.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				enc.AddString("Name", name)
+				return nil
+			})); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newCloudWatchLogger returns a logger encoding with
+// NewCloudWatchEncoderConfig into ws.
+func newCloudWatchLogger(ws zapcore.WriteSyncer) *zap.Logger {
+	return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(NewCloudWatchEncoderConfig()), ws, zapcore.DebugLevel))
+}
+
+func TestNewCloudWatchEncoderConfig(t *testing.T) {
+	ws := newFakeSyncer()
+	before := time.Now().UnixMilli()
+	newCloudWatchLogger(ws).Info("fetched")
+	after := time.Now().UnixMilli()
+
+	dec := json.NewDecoder(strings.NewReader(ws.String()))
+	dec.UseNumber()
+	var entry map[string]interface{}
+	if err := dec.Decode(&entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "info" || entry["message"] != "fetched" {
+		t.Errorf("entry = %v, want level and message keys", entry)
+	}
+	n, ok := entry["timestamp"].(json.Number)
+	if !ok {
+		t.Fatalf("timestamp = %v, want a number", entry["timestamp"])
+	}
+	ts, err := n.Int64()
+	if err != nil {
+		t.Fatalf("timestamp %s is not an integer", n)
+	}
+	if ts < before || ts > after {
+		t.Errorf("timestamp = %d, want milliseconds between %d and %d", ts, before, after)
+	}
+}
+
+func TestEMF(t *testing.T) {
+	ws := newFakeSyncer()
+	newCloudWatchLogger(ws).Info("request served", EMF("demo", map[string]float64{
+		"latency_ms": 12.5,
+		"bytes":      2048,
+	}))
+
+	var got struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name string }
+			}
+		} `json:"_aws"`
+		LatencyMS float64 `json:"latency_ms"`
+		Bytes     float64 `json:"bytes"`
+	}
+	if err := json.Unmarshal([]byte(ws.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.AWS.Timestamp == 0 {
+		t.Error("_aws.Timestamp is missing")
+	}
+	if len(got.AWS.CloudWatchMetrics) != 1 {
+		t.Fatalf("got %d metric directives, want 1: %s", len(got.AWS.CloudWatchMetrics), ws)
+	}
+	directive := got.AWS.CloudWatchMetrics[0]
+	if directive.Namespace != "demo" {
+		t.Errorf("Namespace = %q, want demo", directive.Namespace)
+	}
+	if !reflect.DeepEqual(directive.Dimensions, [][]string{{}}) {
+		t.Errorf("Dimensions = %v, want a single empty set", directive.Dimensions)
+	}
+	var names []string
+	for _, m := range directive.Metrics {
+		names = append(names, m.Name)
+	}
+	if want := []string{"bytes", "latency_ms"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("metric names = %v, want %v", names, want)
+	}
+	if got.LatencyMS != 12.5 || got.Bytes != 2048 {
+		t.Errorf("metric values = %v, %v, want 12.5, 2048", got.LatencyMS, got.Bytes)
+	}
+}
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"os"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
+)
+
+// WithAutoColor switches to the console encoding with capitalized levels, and
+// colors those levels only if standard error is a terminal. Output that is
+// redirected to a file or piped to another program gets no ANSI escape codes.
+//
+// The check is made once, when the option is applied, not for every entry.
+func WithAutoColor() Option {
+	return func(o *options) {
+		o.config.Encoding = "console"
+		o.config.EncoderConfig.EncodeLevel = autoColorLevelEncoder(term.IsTerminal(int(os.Stderr.Fd())))
+	}
+}
+
+func autoColorLevelEncoder(tty bool) zapcore.LevelEncoder {
+	if tty {
+		return zapcore.CapitalColorLevelEncoder
+	}
+	return zapcore.CapitalLevelEncoder
+}
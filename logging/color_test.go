@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithAutoColor(t *testing.T) {
+	// os.Stderr is a regular file here, so the option takes the non-TTY
+	// branch as it would with output redirected to a file.
+	stderr := redirectStderr(t)
+	logger, err := NewLogger(WithAutoColor())
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	logger.Warn("disk almost full")
+	_ = logger.Sync()
+
+	out := stderr()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("output %q contains ANSI escape codes", out)
+	}
+	if !strings.Contains(out, "\tWARN\t") {
+		t.Errorf("output %q is not console-encoded with a capitalized level", out)
+	}
+}
+
+func TestAutoColorLevelEncoder(t *testing.T) {
+	tests := []struct {
+		tty  bool
+		want string
+	}{
+		{false, "WARN"},
+		{true, "\x1b[33mWARN\x1b[0m"},
+	}
+	for _, tt := range tests {
+		cfg := zap.NewProductionEncoderConfig()
+		cfg.EncodeLevel = autoColorLevelEncoder(tt.tty)
+		enc := zapcore.NewConsoleEncoder(cfg)
+		buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Split(buf.String(), "\t")[1]; got != tt.want {
+			t.Errorf("tty %v: level = %q, want %q", tt.tty, got, tt.want)
+		}
+	}
+}
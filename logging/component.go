@@ -0,0 +1,19 @@
+package logging
+
+import "go.uber.org/zap"
+
+// Named returns a child of l for the given component, using l.Named. In JSON
+// output the name appears under the "logger" key, e.g. "logger":"db", and
+// nested names are joined with a dot, so Named(Named(l, "api"), "db") logs
+// "logger":"api.db".
+func Named(l *zap.Logger, component string) *zap.Logger {
+	return l.Named(component)
+}
+
+// WithComponentFields is like Named but also adds a "component" field holding
+// the full dotted name. Call it once per subsystem: calling it again on its
+// own result repeats the "component" key.
+func WithComponentFields(l *zap.Logger, component string) *zap.Logger {
+	named := l.Named(component)
+	return named.With(zap.String("component", named.Name()))
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNamed(t *testing.T) {
+	tests := []struct {
+		name string
+		// build derives the component logger from l.
+		build         func(l *zap.Logger) *zap.Logger
+		wantLogger    string
+		wantComponent string
+	}{
+		{
+			name:       "named",
+			build:      func(l *zap.Logger) *zap.Logger { return Named(l, "db") },
+			wantLogger: "db",
+		},
+		{
+			name:       "nested",
+			build:      func(l *zap.Logger) *zap.Logger { return Named(Named(l, "api"), "db") },
+			wantLogger: "api.db",
+		},
+		{
+			name:          "component fields",
+			build:         func(l *zap.Logger) *zap.Logger { return WithComponentFields(l, "db") },
+			wantLogger:    "db",
+			wantComponent: "db",
+		},
+		{
+			name:          "component fields under a name",
+			build:         func(l *zap.Logger) *zap.Logger { return WithComponentFields(Named(l, "api"), "db") },
+			wantLogger:    "api.db",
+			wantComponent: "api.db",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			tt.build(logger).Info("query")
+
+			entry := entries()[0]
+			if entry["logger"] != tt.wantLogger {
+				t.Errorf("logger = %v, want %q", entry["logger"], tt.wantLogger)
+			}
+			if tt.wantComponent == "" {
+				if c, ok := entry["component"]; ok {
+					t.Errorf("component = %v, want none", c)
+				}
+			} else if entry["component"] != tt.wantComponent {
+				t.Errorf("component = %v, want %q", entry["component"], tt.wantComponent)
+			}
+		})
+	}
+}
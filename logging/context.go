@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+// ContextWithLogger returns a copy of ctx that carries l.
+func ContextWithLogger(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// LoggerFromContext returns the logger stored in ctx by ContextWithLogger.
+// It never returns nil: when ctx carries no logger, a no-op logger is
+// returned so callers can log unconditionally.
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return zap.NewNop()
+}
+
+// WithContextFields returns a copy of ctx whose logger has fields appended,
+// so that everything logged further down the call chain carries them.
+func WithContextFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return ContextWithLogger(ctx, LoggerFromContext(ctx).With(fields...))
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestLoggerFromContext(t *testing.T) {
+	logger, _ := logtest.NewTestLogger()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *zap.Logger
+	}{
+		{"with logger", ContextWithLogger(context.Background(), logger), logger},
+		{"nil logger", ContextWithLogger(context.Background(), nil), nil},
+		{"without logger", context.Background(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LoggerFromContext(tt.ctx)
+			if got == nil {
+				t.Fatal("LoggerFromContext returned nil")
+			}
+			if tt.want != nil && got != tt.want {
+				t.Errorf("LoggerFromContext = %p, want %p", got, tt.want)
+			}
+			if tt.want == nil && got.Core().Enabled(zapcore.FatalLevel) {
+				t.Error("LoggerFromContext without a logger is not a no-op logger")
+			}
+		})
+	}
+}
+
+func TestWithContextFields(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	ctx := ContextWithLogger(context.Background(), logger)
+	ctx = WithContextFields(ctx, zap.String("request_id", "abc"))
+	ctx = WithContextFields(ctx, zap.String("user", "marmotedu"))
+
+	handle(ctx)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	for key, want := range map[string]string{"request_id": "abc", "user": "marmotedu"} {
+		if got, _ := logtest.FieldValue(entries[0], key); got != want {
+			t.Errorf("%s = %v, want %q", key, got, want)
+		}
+	}
+}
+
+// handle stands for a function further down the call chain that only gets
+// the context.
+func handle(ctx context.Context) {
+	LoggerFromContext(ctx).Info("handled")
+}
@@ -0,0 +1,180 @@
+package logging
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var csvPool = buffer.NewPool()
+
+// NewCSVEncoder returns an encoder that renders each entry as one CSV row
+// with a cell per column, in the order of columns whatever the order of the
+// fields in the log call. A column named like one of cfg's keys, such as
+// TimeKey, LevelKey or MessageKey, holds that part of the entry, formatted
+// by cfg's encoders; any other column holds the value of the field of that
+// name, or is empty when the entry has no such field. A dotted column like
+// "request.url" reaches into objects and namespaces. Cells are quoted as RFC
+// 4180 requires, and rows end in cfg.LineEnding if it is "\r\n", or "\n".
+//
+// There is no header row; write columns as one when creating the file.
+func NewCSVEncoder(cfg zapcore.EncoderConfig, columns []string) zapcore.Encoder {
+	return &csvEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		cfg:              &cfg,
+		columns:          columns,
+	}
+}
+
+type csvEncoder struct {
+	// MapObjectEncoder collects the fields added with With.
+	*zapcore.MapObjectEncoder
+	cfg     *zapcore.EncoderConfig
+	columns []string
+}
+
+func (enc *csvEncoder) Clone() zapcore.Encoder {
+	return enc.clone()
+}
+
+func (enc *csvEncoder) clone() *csvEncoder {
+	clone := &csvEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		cfg:              enc.cfg,
+		columns:          enc.columns,
+	}
+	for k, v := range enc.Fields {
+		clone.Fields[k] = v
+	}
+	return clone
+}
+
+func (enc *csvEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := enc.clone()
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+
+	row := make([]string, len(enc.columns))
+	for i, col := range enc.columns {
+		row[i] = final.cell(col, ent)
+	}
+
+	buf := csvPool.Get()
+	w := csv.NewWriter(buf)
+	w.UseCRLF = enc.cfg.LineEnding == "\r\n"
+	if err := w.Write(row); err != nil {
+		buf.Free()
+		return nil, err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		buf.Free()
+		return nil, err
+	}
+	return buf, nil
+}
+
+// cell returns the content of column col for ent.
+func (enc *csvEncoder) cell(col string, ent zapcore.Entry) string {
+	cfg := enc.cfg
+	switch col {
+	case "":
+		return ""
+	case cfg.TimeKey:
+		return enc.timeString(ent.Time)
+	case cfg.LevelKey:
+		if cfg.EncodeLevel == nil {
+			return ent.Level.String()
+		}
+		return enc.element(ent.Level.String(), func(ae zapcore.PrimitiveArrayEncoder) {
+			cfg.EncodeLevel(ent.Level, ae)
+		})
+	case cfg.MessageKey:
+		return ent.Message
+	case cfg.NameKey:
+		return ent.LoggerName
+	case cfg.CallerKey:
+		if !ent.Caller.Defined {
+			return ""
+		}
+		if cfg.EncodeCaller == nil {
+			return ent.Caller.String()
+		}
+		return enc.element(ent.Caller.String(), func(ae zapcore.PrimitiveArrayEncoder) {
+			cfg.EncodeCaller(ent.Caller, ae)
+		})
+	case cfg.FunctionKey:
+		return ent.Caller.Function
+	case cfg.StacktraceKey:
+		return ent.Stack
+	}
+
+	v, ok := lookupField(enc.Fields, col)
+	if !ok {
+		return ""
+	}
+	return enc.valueString(v)
+}
+
+// element runs one of zap's element encoders and returns what it appended,
+// or fallback if it appended nothing.
+func (enc *csvEncoder) element(fallback string, encode func(zapcore.PrimitiveArrayEncoder)) string {
+	values := &logfmtValues{cfg: enc.cfg}
+	encode(values)
+	if len(values.elems) == 0 {
+		return fallback
+	}
+	return strings.Join(values.elems, ",")
+}
+
+// timeString formats t with cfg.EncodeTime, or as epoch nanoseconds
+// without one.
+func (enc *csvEncoder) timeString(t time.Time) string {
+	values := &logfmtValues{cfg: enc.cfg}
+	values.AppendTime(t)
+	return strings.Join(values.elems, ",")
+}
+
+func (enc *csvEncoder) valueString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return enc.timeString(v)
+	case time.Duration:
+		values := &logfmtValues{cfg: enc.cfg}
+		values.AppendDuration(v)
+		return strings.Join(values.elems, ",")
+	case map[string]interface{}, []interface{}:
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// lookupField finds key in fields, descending into nested objects for each
+// dot in key. A key that exists literally, dots included, wins.
+func lookupField(fields map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := fields[key]; ok {
+		return v, true
+	}
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := fields[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupField(nested, rest)
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// csvEncoderConfig is the production encoder config with RFC3339 times.
+func csvEncoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.RFC3339TimeEncoder
+	return cfg
+}
+
+func TestCSVEncoder(t *testing.T) {
+	columns := []string{"ts", "level", "msg", "user", "attempt", "request.url", "missing"}
+	tests := []struct {
+		name   string
+		msg    string
+		fields []zap.Field
+		want   string
+	}{
+		{
+			name:   "plain",
+			msg:    "fetched",
+			fields: []zap.Field{zap.Int("attempt", 3), zap.String("user", "frank")},
+			want:   "2023-10-24T11:06:18Z,info,fetched,frank,3,,\n",
+		},
+		{
+			name:   "quoting",
+			msg:    `failed, "again"`,
+			fields: []zap.Field{zap.String("user", "Doe, Jane"), zap.String("attempt", "line\nbreak")},
+			want:   "2023-10-24T11:06:18Z,info,\"failed, \"\"again\"\"\",\"Doe, Jane\",\"line\nbreak\",,\n",
+		},
+		{
+			name:   "nested",
+			msg:    "fetched",
+			fields: []zap.Field{zap.Namespace("request"), zap.String("url", "http://marmotedu.com")},
+			want:   "2023-10-24T11:06:18Z,info,fetched,,,http://marmotedu.com,\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encodeEntry(t, NewCSVEncoder(csvEncoderConfig(), columns), tt.msg, tt.fields...)
+			if got != tt.want {
+				t.Errorf("row = %q, want %q", got, tt.want)
+			}
+			row, err := csv.NewReader(strings.NewReader(got)).Read()
+			if err != nil {
+				t.Fatalf("row %q does not parse as CSV: %v", got, err)
+			}
+			if len(row) != len(columns) {
+				t.Errorf("row has %d cells, want %d", len(row), len(columns))
+			}
+		})
+	}
+}
+
+func TestCSVEncoderWith(t *testing.T) {
+	cfg := csvEncoderConfig()
+	cfg.LineEnding = "\r\n"
+	enc := NewCSVEncoder(cfg, []string{"msg", "service", "backoff"})
+	enc.AddString("service", "api")
+
+	got := encodeEntry(t, enc.Clone(), "fetched", zap.Duration("backoff", time.Second))
+	if want := "fetched,api,1\r\n"; got != want {
+		t.Errorf("row = %q, want %q", got, want)
+	}
+	row, _ := csv.NewReader(strings.NewReader(got)).Read()
+	if !reflect.DeepEqual(row, []string{"fetched", "api", "1"}) {
+		t.Errorf("parsed row = %q", row)
+	}
+}
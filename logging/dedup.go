@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DedupKeyFunc decides which entries WithDedupFunc treats as identical: two
+// entries are duplicates when it returns the same key for both.
+type DedupKeyFunc func(ent zapcore.Entry, fields []zapcore.Field) string
+
+// DefaultDedupKey treats entries as identical when they have the same level,
+// message and fields.
+func DefaultDedupKey(ent zapcore.Entry, fields []zapcore.Field) string {
+	return ent.Level.String() + "\x00" + ent.Message + "\x00" + encodeFieldsKey(fields)
+}
+
+// MessageDedupKey treats entries as identical when they have the same level
+// and message, whatever their fields.
+func MessageDedupKey(ent zapcore.Entry, _ []zapcore.Field) string {
+	return ent.Level.String() + "\x00" + ent.Message
+}
+
+// WithDedup suppresses repeats of an entry written within window of its first
+// occurrence, using DefaultDedupKey. When the window ends, a single copy of
+// the entry is written again with a "suppressed_count" field holding the
+// number of repeats dropped, unless there were none.
+func WithDedup(window time.Duration) Option {
+	return WithDedupFunc(window, DefaultDedupKey)
+}
+
+// WithDedupFunc is like WithDedup but lets key decide which entries are
+// identical, e.g. MessageDedupKey to ignore fields.
+func WithDedupFunc(window time.Duration, key DedupKeyFunc) Option {
+	return func(o *options) {
+		state := &dedupState{window: window, key: key, pending: make(map[string]*dedupEntry)}
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &dedupCore{Core: core, state: state}
+		})
+	}
+}
+
+type dedupState struct {
+	window time.Duration
+	key    DedupKeyFunc
+
+	mu      sync.Mutex
+	pending map[string]*dedupEntry
+}
+
+// dedupEntry is an entry seen within the current window, kept with the core
+// it was written to so that the summary carries the same context.
+type dedupEntry struct {
+	core       zapcore.Core
+	ent        zapcore.Entry
+	fields     []zapcore.Field
+	suppressed int
+	timer      *time.Timer
+}
+
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+	// contextKey identifies the fields added with With, so that entries
+	// from loggers with different context are not merged.
+	contextKey string
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{
+		Core:       c.Core.With(fields),
+		state:      c.state,
+		contextKey: c.contextKey + encodeFieldsKey(fields),
+	}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key := c.contextKey + "\x00" + c.state.key(ent, fields)
+
+	c.state.mu.Lock()
+	if e, ok := c.state.pending[key]; ok {
+		e.suppressed++
+		c.state.mu.Unlock()
+		return nil
+	}
+	e := &dedupEntry{core: c.Core, ent: ent, fields: fields}
+	e.timer = time.AfterFunc(c.state.window, func() {
+		c.state.flush(key)
+	})
+	c.state.pending[key] = e
+	c.state.mu.Unlock()
+
+	return c.Core.Write(ent, fields)
+}
+
+// Sync writes the summaries of all pending entries before syncing.
+func (c *dedupCore) Sync() error {
+	c.state.mu.Lock()
+	keys := make([]string, 0, len(c.state.pending))
+	for key := range c.state.pending {
+		keys = append(keys, key)
+	}
+	c.state.mu.Unlock()
+
+	for _, key := range keys {
+		c.state.flush(key)
+	}
+	return c.Core.Sync()
+}
+
+func (s *dedupState) flush(key string) {
+	s.mu.Lock()
+	e, ok := s.pending[key]
+	if ok {
+		delete(s.pending, key)
+		e.timer.Stop()
+	}
+	s.mu.Unlock()
+
+	if !ok || e.suppressed == 0 {
+		return
+	}
+	ent := e.ent
+	ent.Time = time.Now()
+	fields := append(e.fields[:len(e.fields):len(e.fields)], zap.Int("suppressed_count", e.suppressed))
+	_ = e.core.Write(ent, fields)
+}
+
+// encodeFieldsKey renders fields into a string that is equal for equal
+// fields, for use in map keys.
+func encodeFieldsKey(fields []zapcore.Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	// fmt prints maps with sorted keys.
+	return fmt.Sprint(enc.Fields)
+}
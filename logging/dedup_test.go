@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWithDedup(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+		// field returns the field of the i-th entry.
+		field func(i int) zap.Field
+		// want is the number of entries written, and wantSuppressed the
+		// suppressed_count of the last one, if any.
+		want, wantSuppressed int
+	}{
+		{"identical", WithDedup(time.Hour), func(int) zap.Field { return zap.String("url", "u") }, 2, 99},
+		{"distinct fields", WithDedup(time.Hour), func(i int) zap.Field { return zap.Int("attempt", i) }, 100, 0},
+		{"message key", WithDedupFunc(time.Hour, MessageDedupKey), func(i int) zap.Field { return zap.Int("attempt", i) }, 2, 99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithSampling(0, 0), tt.opt)
+			for i := 0; i < 100; i++ {
+				logger.Error("connection refused", tt.field(i))
+			}
+
+			got := entries()
+			if len(got) != tt.want {
+				t.Fatalf("got %d entries, want %d", len(got), tt.want)
+			}
+			if _, ok := got[0]["suppressed_count"]; ok {
+				t.Error("the first entry has a suppressed_count")
+			}
+			last, ok := got[len(got)-1]["suppressed_count"]
+			if tt.wantSuppressed == 0 {
+				if ok {
+					t.Errorf("suppressed_count = %v, want none", last)
+				}
+			} else if last != float64(tt.wantSuppressed) {
+				t.Errorf("suppressed_count = %v, want %d", last, tt.wantSuppressed)
+			}
+		})
+	}
+}
+
+func TestWithDedupContext(t *testing.T) {
+	logger, entries := newFileLogger(t, WithSampling(0, 0), WithDedup(time.Hour))
+	for _, user := range []string{"a", "b", "a"} {
+		logger.With(zap.String("user", user)).Info("login")
+	}
+
+	got := entries()
+	if len(got) != 3 {
+		t.Fatalf("got %d entries, want 3", len(got))
+	}
+	if got[2]["user"] != "a" || got[2]["suppressed_count"] != float64(1) {
+		t.Errorf("summary = %v, want user a with suppressed_count 1", got[2])
+	}
+}
+
+func TestWithDedupWindow(t *testing.T) {
+	// Read the file directly, since syncing would write the summary early.
+	path := filepath.Join(t.TempDir(), "out.log")
+	logger, err := NewLogger(WithOutputPaths(path), WithSampling(0, 0), WithDedup(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		logger.Info("retrying")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(readEntries(t, path)) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	got := readEntries(t, path)
+	if len(got) != 2 || got[1]["suppressed_count"] != float64(2) {
+		t.Fatalf("got %v, want the entry and a summary once the window ended", got)
+	}
+
+	logger.Info("retrying")
+	if n := len(readEntries(t, path)); n != 3 {
+		t.Errorf("got %d entries after the window, want the entry logged again", n)
+	}
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DeferredLogger stands in for a logger that cannot be built yet, such as
+// one configured from a file that still has to be loaded. Entries logged
+// through it are held in memory until SetDelegate hands over the real
+// logger; they are then replayed to it, in order and with their original
+// level, time and caller, and later entries go straight through.
+type DeferredLogger struct {
+	logger   *zap.Logger
+	delegate atomic.Pointer[zapcore.Core]
+
+	mu       sync.Mutex
+	capacity int
+	buffered []deferredEntry
+	dropped  int
+}
+
+type deferredEntry struct {
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// NewDeferredLogger returns a DeferredLogger that holds up to capacity
+// entries. When more are logged before SetDelegate, the oldest ones are
+// dropped, and a WarnLevel entry saying how many is replayed in their place.
+//
+// Until SetDelegate, every level is held. A Fatal or Panic entry still ends
+// the program or panics, with the held entries, that one included, lost.
+func NewDeferredLogger(capacity int) *DeferredLogger {
+	if capacity < 1 {
+		capacity = 1
+	}
+	d := &DeferredLogger{capacity: capacity}
+	d.logger = zap.New(&deferredCore{logger: d}, zap.AddCaller())
+	return d
+}
+
+// Logger returns the logger to use until, and after, SetDelegate is called.
+func (d *DeferredLogger) Logger() *zap.Logger {
+	return d.logger
+}
+
+// SetDelegate replays the held entries to l and forwards everything logged
+// afterwards to it. Entries logged while the replay is running wait for it
+// to finish, so that they come after the replayed ones. Calling SetDelegate
+// again switches to another logger; there is nothing left to replay then.
+//
+// Only the core of l is adopted, so that replayed entries keep their own
+// time and caller. Hooks and fields l added to its core apply, but the
+// options zap's Logger handles itself, such as zap.AddStacktrace,
+// zap.AddCaller, zap.WithFatalHook and zap.ErrorOutput, and l's name do not:
+// the logger returned by Logger keeps recording the caller and adds no stack
+// traces.
+func (d *DeferredLogger) SetDelegate(l *zap.Logger) {
+	core := l.Core()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dropped > 0 && len(d.buffered) > 0 {
+		first := d.buffered[0].ent
+		writeTo(core, zapcore.Entry{
+			Level:   zapcore.WarnLevel,
+			Time:    first.Time,
+			Message: "deferred logger dropped entries logged before the delegate was set",
+		}, []zapcore.Field{zap.Int("dropped", d.dropped)})
+	}
+	for _, e := range d.buffered {
+		writeTo(core, e.ent, e.fields)
+	}
+	d.buffered, d.dropped = nil, 0
+	d.delegate.Store(&core)
+}
+
+// hold buffers an entry, unless the delegate has been set in the meantime.
+func (d *DeferredLogger) hold(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Core, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if core := d.delegate.Load(); core != nil {
+		return *core, false
+	}
+	if len(d.buffered) == d.capacity {
+		copy(d.buffered, d.buffered[1:])
+		d.buffered = d.buffered[:len(d.buffered)-1]
+		d.dropped++
+	}
+	// Copied, the caller may reuse its slice once the log call returns.
+	fields = append([]zapcore.Field(nil), fields...)
+	d.buffered = append(d.buffered, deferredEntry{ent: ent, fields: fields})
+	return nil, true
+}
+
+// writeTo writes an entry to core if core accepts it, as a logger would.
+func writeTo(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+type deferredCore struct {
+	logger *DeferredLogger
+	// context holds the fields added with With.
+	context []zapcore.Field
+}
+
+func (c *deferredCore) Enabled(level zapcore.Level) bool {
+	if core := c.logger.delegate.Load(); core != nil {
+		return (*core).Enabled(level)
+	}
+	return true
+}
+
+func (c *deferredCore) With(fields []zapcore.Field) zapcore.Core {
+	context := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	context = append(append(context, c.context...), fields...)
+	return &deferredCore{logger: c.logger, context: context}
+}
+
+func (c *deferredCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *deferredCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.context) > 0 {
+		all := make([]zapcore.Field, 0, len(c.context)+len(fields))
+		fields = append(append(all, c.context...), fields...)
+	}
+	if core := c.logger.delegate.Load(); core != nil {
+		writeTo(*core, ent, fields)
+		return nil
+	}
+	if core, held := c.logger.hold(ent, fields); !held {
+		writeTo(core, ent, fields)
+	}
+	return nil
+}
+
+func (c *deferredCore) Sync() error {
+	if core := c.logger.delegate.Load(); core != nil {
+		return (*core).Sync()
+	}
+	return nil
+}
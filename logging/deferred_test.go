@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+// levelsAndMessages returns "level msg" for each observed entry.
+func levelsAndMessages(logs *observer.ObservedLogs) []string {
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Level.String()+" "+e.Message)
+	}
+	return got
+}
+
+func TestDeferredLogger(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		// level is the delegate's minimum level.
+		level zapcore.Level
+		want  []string
+	}{
+		{
+			name:     "replayed in order",
+			capacity: 10,
+			level:    zapcore.DebugLevel,
+			want:     []string{"debug loading config", "info config loaded", "warn config is stale", "info ready"},
+		},
+		{
+			name:     "capped",
+			capacity: 2,
+			level:    zapcore.DebugLevel,
+			want: []string{
+				"warn deferred logger dropped entries logged before the delegate was set",
+				"info config loaded", "warn config is stale", "info ready",
+			},
+		},
+		{
+			name:     "delegate level",
+			capacity: 10,
+			level:    zapcore.InfoLevel,
+			want:     []string{"info config loaded", "warn config is stale", "info ready"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeferredLogger(tt.capacity)
+			logger := d.Logger()
+			logger.Debug("loading config")
+			logger.Info("config loaded")
+			logger.Warn("config is stale")
+
+			core, logs := observer.New(tt.level)
+			d.SetDelegate(zap.New(core))
+			logger.Info("ready")
+
+			if got := levelsAndMessages(logs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("delegate got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeferredLoggerDropped(t *testing.T) {
+	d := NewDeferredLogger(1)
+	for i := 0; i < 4; i++ {
+		d.Logger().Info("starting")
+	}
+	delegate, logs := logtest.NewTestLogger()
+	d.SetDelegate(delegate)
+
+	if v, _ := logtest.FieldValue(logs.All()[0], "dropped"); v != int64(3) {
+		t.Errorf("dropped = %v, want 3", v)
+	}
+}
+
+func TestDeferredLoggerFields(t *testing.T) {
+	d := NewDeferredLogger(10)
+	child := d.Logger().With(zap.String("component", "config"))
+	fields := []zap.Field{zap.String("path", "/etc/app.yaml")}
+	child.Info("loading", fields...)
+	// A caller may reuse its slice once the call returns.
+	fields[0] = zap.String("path", "overwritten")
+
+	delegate, logs := logtest.NewTestLogger()
+	d.SetDelegate(delegate)
+
+	entry := logs.All()[0]
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{"component", "config"},
+		{"path", "/etc/app.yaml"},
+	}
+	for _, tt := range tests {
+		if got, _ := logtest.FieldValue(entry, tt.key); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+	if !entry.Caller.Defined {
+		t.Error("replayed entry lost its caller")
+	}
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"reflect"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Diff logs what changed between old and new, two values of the same struct
+// type such as a configuration before and after a reload, as an object with
+// one entry per changed field, named like the field in Go:
+//
+//	"changes":{"Level":{"old":"info","new":"debug"}}
+//
+// Unexported fields are skipped, and a field holding a struct is diffed the
+// same way, one level deep, so that a changed nested field shows up as
+// {"Server":{"Port":{"old":8080,"new":9090}}}. Deeper structs, and structs
+// with their own MarshalJSON or MarshalText method like time.Time, are
+// compared as a whole. Pointers to structs are followed, and fields are
+// compared with reflect.DeepEqual.
+//
+// Identical values produce an empty object. Values that are not structs of
+// the same type are compared as a whole too, and logged as {"old":...,
+// "new":...} when they differ.
+func Diff(key string, old, new interface{}) zap.Field {
+	return zap.Object(key, structDiff{old: reflect.ValueOf(old), new: reflect.ValueOf(new), depth: 1})
+}
+
+// structDiff encodes the changed fields of old and new, diffing nested
+// structs while depth is above zero.
+type structDiff struct {
+	old, new reflect.Value
+	depth    int
+}
+
+func (d structDiff) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	old, new := derefStruct(d.old), derefStruct(d.new)
+	if !diffable(old, new) {
+		if valuesEqual(d.old, d.new) {
+			return nil
+		}
+		return changedValue{old: d.old, new: d.new}.MarshalLogObject(enc)
+	}
+
+	t := old.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		of, nf := old.Field(i), new.Field(i)
+		if valuesEqual(of, nf) {
+			continue
+		}
+		if d.depth > 0 && diffable(derefStruct(of), derefStruct(nf)) {
+			if err := enc.AddObject(sf.Name, structDiff{old: of, new: nf, depth: d.depth - 1}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.AddObject(sf.Name, changedValue{old: of, new: nf}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changedValue encodes one change as {"old":...,"new":...}.
+type changedValue struct {
+	old, new reflect.Value
+}
+
+func (c changedValue) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if err := enc.AddReflected("old", valueInterface(c.old)); err != nil {
+		return err
+	}
+	return enc.AddReflected("new", valueInterface(c.new))
+}
+
+// derefStruct follows non-nil pointers in v.
+func derefStruct(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// diffable reports whether old and new are structs of the same type that
+// are diffed field by field.
+func diffable(old, new reflect.Value) bool {
+	if !old.IsValid() || !new.IsValid() || old.Type() != new.Type() || old.Kind() != reflect.Struct {
+		return false
+	}
+	t := old.Type()
+	pt := reflect.PointerTo(t)
+	return !t.Implements(jsonMarshalerType) && !t.Implements(textMarshalerType) &&
+		!pt.Implements(jsonMarshalerType) && !pt.Implements(textMarshalerType)
+}
+
+func valuesEqual(old, new reflect.Value) bool {
+	return reflect.DeepEqual(valueInterface(old), valueInterface(new))
+}
+
+// valueInterface returns the value held by v, or nil for the zero Value.
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type diffServer struct {
+	Host string
+	Port int
+	TLS  diffTLS
+}
+
+type diffTLS struct {
+	Cert string
+}
+
+type diffConfig struct {
+	Level   string
+	Tags    []string
+	Server  diffServer
+	Started time.Time
+	secret  string
+}
+
+func TestDiff(t *testing.T) {
+	start := time.Date(2023, 10, 24, 11, 6, 18, 0, time.UTC)
+	base := diffConfig{
+		Level:   "info",
+		Tags:    []string{"a"},
+		Server:  diffServer{Host: "localhost", Port: 8080, TLS: diffTLS{Cert: "old.pem"}},
+		Started: start,
+		secret:  "hunter2",
+	}
+	tests := []struct {
+		name   string
+		change func(c *diffConfig)
+		want   map[string]interface{}
+	}{
+		{
+			name:   "identical",
+			change: func(c *diffConfig) {},
+			want:   map[string]interface{}{},
+		},
+		{
+			name:   "top-level field",
+			change: func(c *diffConfig) { c.Level = "debug" },
+			want: map[string]interface{}{
+				"Level": map[string]interface{}{"old": "info", "new": "debug"},
+			},
+		},
+		{
+			name:   "slice",
+			change: func(c *diffConfig) { c.Tags = []string{"a", "b"} },
+			want: map[string]interface{}{
+				"Tags": map[string]interface{}{"old": []interface{}{"a"}, "new": []interface{}{"a", "b"}},
+			},
+		},
+		{
+			name:   "nested field",
+			change: func(c *diffConfig) { c.Server.Port = 9090 },
+			want: map[string]interface{}{
+				"Server": map[string]interface{}{"Port": map[string]interface{}{"old": 8080.0, "new": 9090.0}},
+			},
+		},
+		{
+			name:   "two levels deep, compared whole",
+			change: func(c *diffConfig) { c.Server.TLS.Cert = "new.pem" },
+			want: map[string]interface{}{
+				"Server": map[string]interface{}{"TLS": map[string]interface{}{
+					"old": map[string]interface{}{"Cert": "old.pem"},
+					"new": map[string]interface{}{"Cert": "new.pem"},
+				}},
+			},
+		},
+		{
+			name:   "marshaler, compared whole",
+			change: func(c *diffConfig) { c.Started = start.Add(time.Hour) },
+			want: map[string]interface{}{
+				"Started": map[string]interface{}{"old": "2023-10-24T11:06:18Z", "new": "2023-10-24T12:06:18Z"},
+			},
+		},
+		{
+			name:   "unexported",
+			change: func(c *diffConfig) { c.secret = "swordfish" },
+			want:   map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			tt.change(&changed)
+			forms := []struct {
+				name     string
+				old, new interface{}
+			}{
+				{"values", base, changed},
+				{"pointers", &base, &changed},
+			}
+			for _, tc := range forms {
+				logger, entries := newFileLogger(t)
+				logger.Info("config reloaded", Diff("changes", tc.old, tc.new))
+
+				if got := entries()[0]["changes"]; !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s: changes = %#v, want %#v", tc.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffNotStructs(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new interface{}
+		want     map[string]interface{}
+	}{
+		{"equal", 3, 3, map[string]interface{}{}},
+		{"different", 3, 4, map[string]interface{}{"old": 3.0, "new": 4.0}},
+		{"different types", diffTLS{Cert: "a"}, diffServer{}, map[string]interface{}{
+			"old": map[string]interface{}{"Cert": "a"},
+			"new": map[string]interface{}{"Host": "", "Port": 0.0, "TLS": map[string]interface{}{"Cert": ""}},
+		}},
+		{"nil", nil, "set", map[string]interface{}{"old": nil, "new": "set"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			logger.Info("compared", Diff("changes", tt.old, tt.new))
+
+			if got := entries()[0]["changes"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
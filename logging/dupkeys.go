@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithDuplicateKeyDetection watches for entries that carry two fields with
+// the same key, counting those attached with With, which JSON consumers
+// resolve each in their own way. The first time a key is repeated at a call
+// site, a WarnLevel entry naming the key and the call site is logged after
+// the offending entry, which itself is written unchanged.
+//
+// Only the keys of the entry being written are compared, so detection costs
+// no allocations. Keys nested under a zap.Namespace are compared with each
+// other only.
+func WithDuplicateKeyDetection() Option {
+	return func(o *options) {
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &dupKeyCore{Core: core, reported: &sync.Map{}}
+		})
+	}
+}
+
+type dupKeyCore struct {
+	zapcore.Core
+	// context holds the keys of the fields attached with With, since the
+	// last zap.Namespace among them.
+	context []string
+	// reported records the key and call site pairs already warned about.
+	reported *sync.Map
+}
+
+type dupKeySite struct {
+	key    string
+	caller string
+}
+
+func (c *dupKeyCore) With(fields []zapcore.Field) zapcore.Core {
+	context := make([]string, len(c.context), len(c.context)+len(fields))
+	copy(context, c.context)
+	for _, f := range fields {
+		switch {
+		case f.Type == zapcore.NamespaceType:
+			context = context[:0:0]
+		case f.Key != "":
+			context = append(context, f.Key)
+		}
+	}
+	return &dupKeyCore{Core: c.Core.With(fields), context: context, reported: c.reported}
+}
+
+func (c *dupKeyCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *dupKeyCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	if key, ok := c.duplicateKey(fields); ok && c.Enabled(zapcore.WarnLevel) {
+		site := dupKeySite{key: key, caller: ent.Caller.String()}
+		if _, seen := c.reported.LoadOrStore(site, struct{}{}); !seen {
+			warn := zapcore.Entry{
+				Level:      zapcore.WarnLevel,
+				Time:       ent.Time,
+				LoggerName: ent.LoggerName,
+				Message:    "duplicate log field key",
+				Caller:     ent.Caller,
+			}
+			_ = c.Core.Write(warn, []zapcore.Field{
+				zap.String("duplicate_key", key),
+				zap.String("logged_message", ent.Message),
+			})
+		}
+	}
+	return err
+}
+
+// duplicateKey returns the first key of fields that appears earlier in the
+// entry, among fields or the context.
+func (c *dupKeyCore) duplicateKey(fields []zapcore.Field) (string, bool) {
+	context, start := c.context, 0
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			context, start = nil, i+1
+			continue
+		}
+		if f.Key == "" {
+			// zap.Inline and zap.Skip fields have no key of their own.
+			continue
+		}
+		for _, k := range context {
+			if k == f.Key {
+				return f.Key, true
+			}
+		}
+		for _, prev := range fields[start:i] {
+			if prev.Key == f.Key {
+				return f.Key, true
+			}
+		}
+	}
+	return "", false
+}
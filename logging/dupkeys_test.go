@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestWithDuplicateKeyDetection(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *zap.Logger)
+		// want is the duplicate key warned about, or "" for no warning.
+		want string
+	}{
+		{
+			name: "same call",
+			log:  func(l *zap.Logger) { l.Info("fetched", zap.String("id", "a"), zap.String("id", "b")) },
+			want: "id",
+		},
+		{
+			name: "context and call",
+			log:  func(l *zap.Logger) { l.With(zap.String("user", "frank")).Info("fetched", zap.String("user", "anna")) },
+			want: "user",
+		},
+		{
+			name: "distinct keys",
+			log:  func(l *zap.Logger) { l.Info("fetched", zap.String("id", "a"), zap.String("url", "b")) },
+		},
+		{
+			name: "separated by a namespace",
+			log: func(l *zap.Logger) {
+				l.Info("fetched", zap.String("id", "a"), zap.Namespace("request"), zap.String("id", "b"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithDuplicateKeyDetection())
+			for i := 0; i < 3; i++ {
+				tt.log(logger)
+			}
+
+			got := entries()
+			var warnings []map[string]interface{}
+			for _, e := range got {
+				if e["msg"] == "duplicate log field key" {
+					warnings = append(warnings, e)
+				}
+			}
+			if len(got)-len(warnings) != 3 {
+				t.Errorf("got %d logged entries, want all 3", len(got)-len(warnings))
+			}
+			if tt.want == "" {
+				if len(warnings) != 0 {
+					t.Errorf("got warnings %v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 {
+				t.Fatalf("got %d warnings, want one for the call site", len(warnings))
+			}
+			w := warnings[0]
+			if w["level"] != "warn" || w["duplicate_key"] != tt.want || w["logged_message"] != "fetched" {
+				t.Errorf("warning = %v, want one naming %q", w, tt.want)
+			}
+			if w["caller"] != got[0]["caller"] {
+				t.Errorf("warning caller = %v, want the call site %v", w["caller"], got[0]["caller"])
+			}
+		})
+	}
+}
+
+func TestWithDuplicateKeyDetectionUnchanged(t *testing.T) {
+	logger, entries := newFileLogger(t, WithDuplicateKeyDetection(), WithZapOptions(zap.WithCaller(false)))
+	logger.Info("fetched", zap.String("id", "a"), zap.String("id", "b"))
+
+	got := entries()
+	if len(got) != 2 || got[0]["msg"] != "fetched" || got[0]["id"] != "b" {
+		t.Errorf("got entries %v, want the entry as logged followed by the warning", got)
+	}
+}
@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithDurationFormat chooses how time.Duration fields are encoded:
+//
+//   - "seconds" (the default): floating-point seconds, 1500ms is 1.5
+//   - "millis": floating-point milliseconds, 1500ms is 1500
+//   - "string": time.Duration.String, 1500ms is "1.5s"
+//   - "nanos": integer nanoseconds, 1500ms is 1500000000
+//
+// Build fails for any other format.
+func WithDurationFormat(format string) Option {
+	return func(o *options) {
+		var enc zapcore.DurationEncoder
+		switch format {
+		case "seconds":
+			enc = zapcore.SecondsDurationEncoder
+		case "millis":
+			enc = zapcore.MillisDurationEncoder
+		case "string":
+			enc = zapcore.StringDurationEncoder
+		case "nanos":
+			enc = zapcore.NanosDurationEncoder
+		default:
+			o.setErr(fmt.Errorf("logging: unknown duration format %q", format))
+			return
+		}
+		o.config.EncoderConfig.EncodeDuration = enc
+	}
+}
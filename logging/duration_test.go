@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWithDurationFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   interface{}
+	}{
+		{"", 1.5},
+		{"seconds", 1.5},
+		{"millis", float64(1500)},
+		{"string", "1.5s"},
+		{"nanos", float64(1500000000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var opts []Option
+			if tt.format != "" {
+				opts = append(opts, WithDurationFormat(tt.format))
+			}
+			logger, entries := newFileLogger(t, opts...)
+			logger.Info("retrying", zap.Duration("backoff", 1500*time.Millisecond))
+
+			if got := entries()[0]["backoff"]; got != tt.want {
+				t.Errorf("backoff = %v (%T), want %v", got, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDurationFormatUnknown(t *testing.T) {
+	if _, err := NewLogger(WithDurationFormat("minutes")); err == nil {
+		t.Error("NewLogger succeeded with an unknown duration format")
+	}
+}
@@ -0,0 +1,155 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// ecsTimeLayout is RFC3339Nano cut down to the millisecond precision ECS
+// expects.
+const ecsTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// ecsEncoding names the encoder returned by NewECSEncoder in zap.Config.
+const ecsEncoding = "ecs"
+
+func init() {
+	_ = zap.RegisterEncoder(ecsEncoding, func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return NewECSEncoder(cfg), nil
+	})
+}
+
+// NewECSEncoderConfig returns an encoder config whose keys follow the Elastic
+// Common Schema: @timestamp, log.level, log.logger, log.origin and message.
+// Use it with NewECSEncoder, or the "ecs" encoding in a zap.Config, which
+// write stacktraces as error.stack_trace inside the nested error object. A
+// plain JSON encoder writes them under the dotted key "error.stack_trace"
+// instead, which only lines up with the error object once ingest expands
+// dotted keys.
+func NewECSEncoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.TimeKey = "@timestamp"
+	cfg.LevelKey = "log.level"
+	cfg.NameKey = "log.logger"
+	cfg.CallerKey = "log.origin"
+	cfg.MessageKey = "message"
+	cfg.StacktraceKey = "error.stack_trace"
+	cfg.EncodeTime = zapcore.TimeEncoderOfLayout(ecsTimeLayout)
+	return cfg
+}
+
+// NewECSEncoder returns a JSON encoder for ECS documents. An entry's
+// stacktrace is written as "stack_trace" inside the "error" object, next to
+// "message", rather than at the top level, and a zap.Error field is written
+// as that object, like ECSError, instead of as a string. When the error
+// already carries a stack of its own, that one is kept. Setting
+// cfg.StacktraceKey to "" leaves stacktraces out.
+//
+// Should "error" be taken by a field that is not an error, the stacktrace is
+// written under the dotted key "error.stack_trace".
+func NewECSEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	stacktraceKey := cfg.StacktraceKey
+	cfg.StacktraceKey = ecsStackTraceKey
+	return ecsEncoder{Encoder: zapcore.NewJSONEncoder(cfg), stacks: stacktraceKey != ""}
+}
+
+// ecsStackTraceKey is where a stacktrace goes when there is no error object
+// to put it in.
+const ecsStackTraceKey = "error.stack_trace"
+
+type ecsEncoder struct {
+	zapcore.Encoder
+	// stacks is false when stacktraces are left out.
+	stacks bool
+}
+
+func (e ecsEncoder) Clone() zapcore.Encoder {
+	return ecsEncoder{Encoder: e.Encoder.Clone(), stacks: e.stacks}
+}
+
+func (e ecsEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	stack := ent.Stack
+	if !e.stacks {
+		stack, ent.Stack = "", ""
+	}
+
+	var out []zapcore.Field
+	hasError, nested := false, false
+	for i, f := range fields {
+		if f.Key != "error" {
+			continue
+		}
+		hasError = true
+		ee, ok := ecsErrorField(f)
+		if !ok {
+			continue
+		}
+		ee.stack = stack
+		if out == nil {
+			out = append([]zapcore.Field(nil), fields...)
+		}
+		out[i] = zap.Object("error", ee)
+		nested = true
+	}
+	if out != nil {
+		fields = out
+	}
+	if nested {
+		ent.Stack = ""
+	} else if !hasError && stack != "" {
+		fields = append(fields[:len(fields):len(fields)], zap.Object("error", ecsError{stack: stack}))
+		ent.Stack = ""
+	}
+	return e.Encoder.EncodeEntry(ent, fields)
+}
+
+// ecsErrorField returns the ECS error object f holds, if it is an ECSError
+// or a non-nil zap.Error field.
+func ecsErrorField(f zapcore.Field) (ecsError, bool) {
+	switch f.Type {
+	case zapcore.ObjectMarshalerType:
+		ee, ok := f.Interface.(ecsError)
+		return ee, ok
+	case zapcore.ErrorType:
+		err, ok := f.Interface.(error)
+		return ecsError{err: err}, ok && err != nil
+	}
+	return ecsError{}, false
+}
+
+// ECSError logs err as the ECS error object, {"message": ..., "type": ...},
+// adding "stack_trace" when err carries a stack (see StackTracer). A nil err
+// adds nothing.
+func ECSError(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object("error", ecsError{err: err})
+}
+
+// ecsError is the ECS error object. Either err or stack may be unset.
+type ecsError struct {
+	err error
+	// stack is the entry's stacktrace, used unless err has a stack itself.
+	stack string
+}
+
+func (e ecsError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	stack := e.stack
+	if e.err != nil {
+		enc.AddString("message", e.err.Error())
+		enc.AddString("type", fmt.Sprintf("%T", e.err))
+		var st StackTracer
+		if errors.As(e.err, &st) {
+			stack = strings.TrimPrefix(fmt.Sprintf("%+v", st.StackTrace()), "\n")
+		}
+	}
+	if stack != "" {
+		enc.AddString("stack_trace", stack)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestECSEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []zap.Field
+		stack  string
+		// noStacks clears the config's StacktraceKey.
+		noStacks bool
+		// want is the error object, or nil if there should be none.
+		want map[string]interface{}
+		// wantStackFrom, if set, must be part of error.stack_trace.
+		wantStackFrom string
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:  "stack only",
+			stack: "main.main\n\tdemo-1.go:30",
+			want:  map[string]interface{}{"stack_trace": "main.main\n\tdemo-1.go:30"},
+		},
+		{
+			name:   "zap.Error",
+			fields: []zap.Field{zap.Error(errors.New("connection refused"))},
+			stack:  "main.main\n\tdemo-1.go:30",
+			want: map[string]interface{}{
+				"message":     "connection refused",
+				"type":        "*errors.errorString",
+				"stack_trace": "main.main\n\tdemo-1.go:30",
+			},
+		},
+		{
+			name:   "ECSError",
+			fields: []zap.Field{ECSError(errors.New("connection refused"))},
+			want: map[string]interface{}{
+				"message": "connection refused",
+				"type":    "*errors.errorString",
+			},
+		},
+		{
+			name:          "error with its own stack",
+			fields:        []zap.Field{ECSError(pkgerrors.New("connection refused"))},
+			stack:         "main.main\n\tdemo-1.go:30",
+			wantStackFrom: "TestECSEncoder",
+		},
+		{
+			name:     "stacktraces left out",
+			fields:   []zap.Field{zap.Error(errors.New("connection refused"))},
+			stack:    "main.main\n\tdemo-1.go:30",
+			noStacks: true,
+			want: map[string]interface{}{
+				"message": "connection refused",
+				"type":    "*errors.errorString",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewECSEncoderConfig()
+			if tt.noStacks {
+				cfg.StacktraceKey = ""
+			}
+			buf, err := NewECSEncoder(cfg).EncodeEntry(zapcore.Entry{
+				Level:      zapcore.ErrorLevel,
+				Time:       testEntryTime,
+				LoggerName: "api",
+				Message:    "failed to fetch URL",
+				Stack:      tt.stack,
+			}, tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("%s: %v", buf, err)
+			}
+
+			for key, want := range map[string]string{
+				"@timestamp": "2023-10-24T11:06:18.000Z",
+				"log.level":  "error",
+				"log.logger": "api",
+				"message":    "failed to fetch URL",
+			} {
+				if doc[key] != want {
+					t.Errorf("%s = %v, want %q", key, doc[key], want)
+				}
+			}
+			for key := range doc {
+				if strings.HasPrefix(key, "error.") {
+					t.Errorf("document has the dotted key %q: %s", key, buf)
+				}
+			}
+
+			errObj, _ := doc["error"].(map[string]interface{})
+			if tt.wantStackFrom != "" {
+				if stack, _ := errObj["stack_trace"].(string); !strings.Contains(stack, tt.wantStackFrom) {
+					t.Errorf("error.stack_trace = %q, want the error's own stack", stack)
+				}
+				return
+			}
+			if tt.want == nil {
+				if _, ok := doc["error"]; ok {
+					t.Errorf("error = %v, want none", doc["error"])
+				}
+				return
+			}
+			got, _ := json.Marshal(errObj)
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) {
+				t.Errorf("error = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestECSEncoding(t *testing.T) {
+	logger, entries := newFileLogger(t, func(o *options) {
+		o.config.Encoding = ecsEncoding
+		o.config.EncoderConfig = NewECSEncoderConfig()
+	})
+	logger.Error("failed to fetch URL", zap.Error(errors.New("connection refused")))
+
+	entry := entries()[0]
+	errObj, _ := entry["error"].(map[string]interface{})
+	if errObj["message"] != "connection refused" {
+		t.Errorf("error.message = %v, want the error", errObj["message"])
+	}
+	if stack, _ := errObj["stack_trace"].(string); !strings.Contains(stack, "TestECSEncoding") {
+		t.Errorf("error.stack_trace = %q, want the entry's stacktrace", stack)
+	}
+}
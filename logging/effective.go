@@ -0,0 +1,120 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// LogConfigSnapshot describes the settings of a running logger, as reported
+// by EffectiveConfig. It marshals to JSON with snake_case keys.
+type LogConfigSnapshot struct {
+	Level            string   `json:"level"`
+	Encoding         string   `json:"encoding"`
+	OutputPaths      []string `json:"output_paths"`
+	ErrorOutputPaths []string `json:"error_output_paths"`
+	// Sampling is nil when the logger does not sample.
+	Sampling *SamplingSnapshot `json:"sampling"`
+	// TimeFormat is the reference time 2006-01-02 15:04:05 -0700 written
+	// the way the logger writes timestamps, which for a layout is the layout
+	// itself.
+	TimeFormat string `json:"time_format"`
+}
+
+// SamplingSnapshot is the sampling part of a LogConfigSnapshot.
+type SamplingSnapshot struct {
+	Initial    int `json:"initial"`
+	Thereafter int `json:"thereafter"`
+}
+
+// EffectiveConfig reports the settings l was built with by Build or
+// NewLogger. Level is read at the time of the call, so it follows changes
+// made at runtime, for example to the level given to WithAtomicLevel through
+// LevelHandler; the rest is what the logger was built with. For a logger
+// that Build did not return, or whose core was replaced with zap.WrapCore
+// since, only Level is set.
+//
+// Output paths are reported without credentials: the password in a URL's
+// user info and the values of its query parameters are replaced by "xxxxx".
+func EffectiveConfig(l *zap.Logger) LogConfigSnapshot {
+	c, ok := l.Core().(*builtCore)
+	if !ok {
+		return LogConfigSnapshot{Level: l.Level().String()}
+	}
+	snapshot := c.config
+	snapshot.Level = levelString(c.level)
+	return snapshot
+}
+
+// ServeHTTP writes s as JSON.
+func (s LogConfigSnapshot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s)
+}
+
+// LogConfigHandler serves EffectiveConfig(l) as JSON, freshly taken for
+// every request, for an endpoint like /debug/logconfig.
+func LogConfigHandler(l *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		EffectiveConfig(l).ServeHTTP(w, r)
+	})
+}
+
+// effectiveConfig returns the settings of a logger built from o, with cfg
+// being the zap config passed to zap.Config.Build. Level is left for
+// EffectiveConfig to fill in.
+func (o *options) effectiveConfig(cfg zap.Config) LogConfigSnapshot {
+	snapshot := LogConfigSnapshot{
+		Encoding:         o.config.Encoding,
+		OutputPaths:      redactPaths(o.config.OutputPaths),
+		ErrorOutputPaths: redactPaths(o.config.ErrorOutputPaths),
+	}
+	if len(o.sinks) > 0 {
+		snapshot.OutputPaths = nil
+		for _, f := range o.rotatingFiles {
+			snapshot.OutputPaths = append(snapshot.OutputPaths, f.Filename)
+		}
+	}
+	if s := o.config.Sampling; s != nil {
+		snapshot.Sampling = &SamplingSnapshot{Initial: s.Initial, Thereafter: s.Thereafter}
+	}
+	if cfg.EncoderConfig.EncodeTime != nil {
+		snapshot.TimeFormat = encodeSampleTime(cfg.EncoderConfig.EncodeTime)
+	}
+	return snapshot
+}
+
+// redactPaths returns paths with the credentials in URLs masked.
+func redactPaths(paths []string) []string {
+	redacted := make([]string, len(paths))
+	for i, path := range paths {
+		redacted[i] = redactPath(path)
+	}
+	return redacted
+}
+
+func redactPath(path string) string {
+	u, err := url.Parse(path)
+	if err != nil || u.Scheme == "" || filepath.VolumeName(path) != "" {
+		// A plain file path, or unparsable, in which case nothing is known
+		// about where credentials might be.
+		if err != nil {
+			return "xxxxx"
+		}
+		return path
+	}
+	if u.User == nil && u.RawQuery == "" {
+		return path
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{"xxxxx"}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
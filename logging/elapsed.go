@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Since logs the time elapsed since start as a duration, encoded as
+// WithDurationFormat chooses. The elapsed time is taken when Since is called,
+// not when the entry is written.
+func Since(key string, start time.Time) zap.Field {
+	return zap.Duration(key, time.Since(start))
+}
+
+// Timer measures how long something takes, for the common pattern of
+// logging the duration of a function when it returns:
+//
+//	t := logging.StartTimer("elapsed")
+//	defer func() { logger.Info("request handled", t.Stop()) }()
+//
+// The deferred closure matters: in `defer logger.Info("...", t.Stop())` the
+// arguments, and so the elapsed time, would be evaluated straight away.
+type Timer struct {
+	key   string
+	start time.Time
+}
+
+// StartTimer returns a Timer started now whose field is called key.
+func StartTimer(key string) Timer {
+	return Timer{key: key, start: time.Now()}
+}
+
+// Stop returns the time elapsed since StartTimer as a Since field. A Timer
+// may be stopped more than once, each time measuring from the same start.
+func (t Timer) Stop() zap.Field {
+	return Since(t.key, t.start)
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSince(t *testing.T) {
+	start := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	f := Since("elapsed", start)
+	at := time.Duration(f.Integer)
+	// Written later, the field keeps the time elapsed when it was made.
+	time.Sleep(10 * time.Millisecond)
+
+	if f.Type != zapcore.DurationType {
+		t.Fatalf("Type = %v, want a duration", f.Type)
+	}
+	if at < 10*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least the 10ms slept", at)
+	}
+	if left := time.Since(start) - at; left < 10*time.Millisecond {
+		t.Errorf("elapsed = %v, want it taken before the second sleep", at)
+	}
+
+	tests := []struct {
+		format string
+		check  func(v interface{}) bool
+	}{
+		{"seconds", func(v interface{}) bool { s, ok := v.(float64); return ok && s >= 0.01 }},
+		{"millis", func(v interface{}) bool { ms, ok := v.(float64); return ok && ms >= 10 }},
+		{"nanos", func(v interface{}) bool { ns, ok := v.(float64); return ok && ns >= 1e7 }},
+		{"string", func(v interface{}) bool { s, ok := v.(string); return ok && s == at.String() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithDurationFormat(tt.format))
+			logger.Info("done", f)
+
+			if v := entries()[0]["elapsed"]; !tt.check(v) {
+				t.Errorf("elapsed = %#v, want %v in %s", v, at, tt.format)
+			}
+		})
+	}
+}
+
+func TestTimer(t *testing.T) {
+	timer := StartTimer("elapsed")
+	time.Sleep(10 * time.Millisecond)
+	first := timer.Stop()
+	time.Sleep(10 * time.Millisecond)
+	second := timer.Stop()
+
+	if first.Key != "elapsed" {
+		t.Errorf("Key = %q, want elapsed", first.Key)
+	}
+	if d := time.Duration(first.Integer); d < 10*time.Millisecond {
+		t.Errorf("first Stop = %v, want at least 10ms", d)
+	}
+	if d := time.Duration(second.Integer - first.Integer); d < 10*time.Millisecond {
+		t.Errorf("second Stop is %v after the first, want it measured from the same start", d)
+	}
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The XxxEnabled helpers report whether l would log at that level, for
+// guarding log calls whose arguments are expensive to compute:
+//
+//	if logging.DebugEnabled(logger) {
+//		sugar.Debugf("state: %s", dumpState())
+//	}
+//
+// They ask l's core on every call, so a level changed through an
+// AtomicLevel is reflected immediately.
+
+// DebugEnabled reports whether l logs at DebugLevel.
+func DebugEnabled(l *zap.Logger) bool {
+	return l.Core().Enabled(zapcore.DebugLevel)
+}
+
+// InfoEnabled reports whether l logs at InfoLevel.
+func InfoEnabled(l *zap.Logger) bool {
+	return l.Core().Enabled(zapcore.InfoLevel)
+}
+
+// WarnEnabled reports whether l logs at WarnLevel.
+func WarnEnabled(l *zap.Logger) bool {
+	return l.Core().Enabled(zapcore.WarnLevel)
+}
+
+// ErrorEnabled reports whether l logs at ErrorLevel.
+func ErrorEnabled(l *zap.Logger) bool {
+	return l.Core().Enabled(zapcore.ErrorLevel)
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEnabledHelpers(t *testing.T) {
+	lvl := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	logger, err := NewLogger(WithAtomicLevel(lvl), WithOutputPaths("stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	helpers := []struct {
+		name    string
+		enabled func(*zap.Logger) bool
+		level   zapcore.Level
+	}{
+		{"DebugEnabled", DebugEnabled, zapcore.DebugLevel},
+		{"InfoEnabled", InfoEnabled, zapcore.InfoLevel},
+		{"WarnEnabled", WarnEnabled, zapcore.WarnLevel},
+		{"ErrorEnabled", ErrorEnabled, zapcore.ErrorLevel},
+	}
+	for _, set := range []zapcore.Level{zapcore.DebugLevel, zapcore.WarnLevel, zapcore.FatalLevel, zapcore.InfoLevel} {
+		lvl.SetLevel(set)
+		for _, h := range helpers {
+			if got, want := h.enabled(logger), h.level >= set; got != want {
+				t.Errorf("at %s: %s = %v, want %v", set, h.name, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewForEnv picks a logger suited to the environment the program runs in:
+//
+//   - "dev" or "local": zap's development logger, console-encoded at
+//     DebugLevel with colored levels, callers, and stacktraces from Warn on
+//   - "prod" or "production": the JSON logger built by NewLogger
+//   - "test": a no-op logger
+//
+// Any other env, including an empty one, gets the production logger, which
+// then logs a warning naming the unknown environment.
+func NewForEnv(env string) (*zap.Logger, error) {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "dev", "local":
+		config := zap.NewDevelopmentConfig()
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		return config.Build()
+	case "prod", "production":
+		return NewLogger()
+	case "test":
+		return zap.NewNop(), nil
+	default:
+		logger, err := NewLogger()
+		if err != nil {
+			return nil, err
+		}
+		logger.Warn("unknown environment, using the production logger", zap.String("env", env))
+		return logger, nil
+	}
+}
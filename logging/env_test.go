@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewForEnv(t *testing.T) {
+	tests := []struct {
+		env string
+		// level is the lowest enabled level, or InvalidLevel for a no-op
+		// logger.
+		level zapcore.Level
+		// check inspects what the logger wrote to stderr.
+		check func(t *testing.T, out string)
+	}{
+		{"dev", zapcore.DebugLevel, checkConsole},
+		{"Local", zapcore.DebugLevel, checkConsole},
+		{"prod", zapcore.InfoLevel, checkJSON},
+		{"production", zapcore.InfoLevel, checkJSON},
+		{"test", zapcore.InvalidLevel, func(t *testing.T, out string) {
+			if out != "" {
+				t.Errorf("no-op logger wrote %q", out)
+			}
+		}},
+		{"staging", zapcore.InfoLevel, func(t *testing.T, out string) {
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("got lines %q, want the warning and the entry", lines)
+			}
+			warning := decodeLine(t, lines[0])
+			if warning["level"] != "warn" || warning["env"] != "staging" {
+				t.Errorf("first line = %v, want a warning naming the environment", warning)
+			}
+			checkJSON(t, lines[1])
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			stderr := redirectStderr(t)
+			logger, err := NewForEnv(tt.env)
+			if err != nil {
+				t.Fatalf("NewForEnv: %v", err)
+			}
+			if got := zapcore.LevelOf(logger.Core()); got != tt.level {
+				t.Errorf("level = %s, want %s", got, tt.level)
+			}
+			logger.Warn("disk almost full")
+			_ = logger.Sync()
+			tt.check(t, stderr())
+		})
+	}
+}
+
+// checkConsole checks that out is a colored console entry with a caller and
+// a stacktrace.
+func checkConsole(t *testing.T, out string) {
+	t.Helper()
+	if strings.HasPrefix(out, "{") {
+		t.Errorf("output %q is JSON, want console", out)
+	}
+	if !strings.Contains(out, "\x1b[") {
+		t.Errorf("output %q has no colored level", out)
+	}
+	if !strings.Contains(out, "env_test.go") {
+		t.Errorf("output %q has no caller", out)
+	}
+	if !strings.Contains(out, "TestNewForEnv") {
+		t.Errorf("output %q has no stacktrace", out)
+	}
+}
+
+// checkJSON checks that out is a single JSON entry with a caller.
+func checkJSON(t *testing.T, out string) {
+	t.Helper()
+	entry := decodeLine(t, strings.TrimSpace(out))
+	if entry["msg"] != "disk almost full" {
+		t.Errorf("msg = %v, want the logged message", entry["msg"])
+	}
+	if _, ok := entry["caller"]; !ok {
+		t.Error("entry has no caller")
+	}
+}
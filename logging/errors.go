@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StackTracer is implemented by errors that carry the stack they were created
+// or wrapped at, such as those from github.com/pkg/errors.
+type StackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// LogError logs msg at ErrorLevel with err attached under the "error" key.
+// When err, or any error it wraps, implements StackTracer, that stack is
+// added as a "stacktrace" string field as well. That stack shows where the
+// error came from, so it replaces the one the logger would otherwise capture
+// at the LogError call.
+//
+// A nil err logs msg and fields alone.
+func LogError(l *zap.Logger, msg string, err error, fields ...zap.Field) {
+	l = helperLogger(l)
+	if err == nil {
+		l.Error(msg, fields...)
+		return
+	}
+
+	// Capped, so that appending cannot write into the caller's array.
+	fields = fields[:len(fields):len(fields)]
+	fields = append(fields, zap.Error(err))
+	var st StackTracer
+	if errors.As(err, &st) {
+		stack := strings.TrimPrefix(fmt.Sprintf("%+v", st.StackTrace()), "\n")
+		fields = append(fields, zap.String("stacktrace", stack))
+		l = withoutStacktrace(l)
+	}
+	l.Error(msg, fields...)
+}
+
+// withoutStacktrace returns l without its own stacktrace capture, for entries
+// that carry a more useful stack in a field of their own.
+func withoutStacktrace(l *zap.Logger) *zap.Logger {
+	return l.WithOptions(zap.AddStacktrace(zap.LevelEnablerFunc(func(zapcore.Level) bool {
+		return false
+	})))
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestLogError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		// wantStack reports whether the error's own stack is logged.
+		wantStack bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("connection refused"), false},
+		{"stack", pkgerrors.New("connection refused"), true},
+		{"wrapped stack", fmt.Errorf("fetch: %w", pkgerrors.New("connection refused")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			logger := zap.New(core, zap.AddStacktrace(zapcore.ErrorLevel))
+			LogError(logger, "failed to fetch URL", tt.err, zap.Int("attempt", 3))
+
+			logtest.AssertLogged(t, logs, zapcore.ErrorLevel, "failed to fetch URL")
+			entry := logs.All()[0]
+			if got, _ := logtest.FieldValue(entry, "attempt"); got != int64(3) {
+				t.Errorf("attempt = %v, want 3", got)
+			}
+			errMsg, hasErr := logtest.FieldValue(entry, "error")
+			if tt.err == nil {
+				if hasErr {
+					t.Errorf("error = %v, want none", errMsg)
+				}
+			} else if errMsg != tt.err.Error() {
+				t.Errorf("error = %v, want %q", errMsg, tt.err.Error())
+			}
+
+			stack, hasStack := logtest.FieldValue(entry, "stacktrace")
+			if hasStack != tt.wantStack {
+				t.Fatalf("stacktrace field present = %v, want %v", hasStack, tt.wantStack)
+			}
+			if !tt.wantStack {
+				if entry.Stack == "" {
+					t.Error("the logger's own stacktrace is missing")
+				}
+				return
+			}
+			if !strings.Contains(stack.(string), "TestLogError") {
+				t.Errorf("stacktrace %q does not lead back to the test", stack)
+			}
+			if entry.Stack != "" {
+				t.Error("the logger's own stacktrace was captured next to the error's")
+			}
+		})
+	}
+}
+
+func TestLogErrorKeepsCallerFields(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"plain", errors.New("connection refused")},
+		{"stack", pkgerrors.New("connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := logtest.NewTestLogger()
+			backing := make([]zap.Field, 1, 4)
+			backing[0] = zap.Int("attempt", 3)
+			spare := backing[:cap(backing)]
+
+			LogError(logger, "failed to fetch URL", tt.err, backing...)
+
+			for i, f := range spare[1:] {
+				if f.Key != "" {
+					t.Errorf("spare element %d = %q, want the caller's array left alone", i+1, f.Key)
+				}
+			}
+		})
+	}
+}
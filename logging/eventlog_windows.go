@@ -0,0 +1,90 @@
+//go:build windows
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLogKey is the registry key under which Application event sources are
+// registered.
+const eventLogKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application`
+
+// eventLogEventID is the event ID every entry is reported with.
+const eventLogEventID = 1
+
+// NewEventLogSyncer returns a WriteSyncer that reports every written JSON
+// line to the Windows Event Log under source. Entries at ErrorLevel and above
+// become Error events, WarnLevel entries Warning events and the rest
+// Information events; the level is read from the line's "level" key.
+//
+// The source is registered if it does not exist yet, which needs
+// administrator rights: a failed registration is returned as an error.
+//
+// The returned syncer has a Close method that releases the event log handle.
+func NewEventLogSyncer(source string) (zapcore.WriteSyncer, error) {
+	if source == "" {
+		return nil, errors.New("logging: event log source must not be empty")
+	}
+	if err := registerEventSource(source); err != nil {
+		return nil, err
+	}
+	l, err := eventlog.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("logging: open event log %q: %w", source, err)
+	}
+	return &eventLogSyncer{log: l}, nil
+}
+
+func registerEventSource(source string) error {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, eventLogKey+`\`+source, registry.QUERY_VALUE)
+	if err == nil {
+		k.Close()
+		return nil
+	}
+	if !errors.Is(err, registry.ErrNotExist) {
+		return fmt.Errorf("logging: look up event source %q: %w", source, err)
+	}
+	err = eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+	if err != nil {
+		return fmt.Errorf("logging: register event source %q: %w", source, err)
+	}
+	return nil
+}
+
+type eventLogSyncer struct {
+	log *eventlog.Log
+}
+
+func (s *eventLogSyncer) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	var err error
+	switch lineLevel(p) {
+	case zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel:
+		err = s.log.Error(eventLogEventID, msg)
+	case zapcore.WarnLevel:
+		err = s.log.Warning(eventLogEventID, msg)
+	default:
+		err = s.log.Info(eventLogEventID, msg)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("logging: report event: %w", err)
+	}
+	return len(p), nil
+}
+
+// Sync does nothing: events are reported as they are written.
+func (s *eventLogSyncer) Sync() error {
+	return nil
+}
+
+// Close releases the event log handle.
+func (s *eventLogSyncer) Close() error {
+	return s.log.Close()
+}
@@ -0,0 +1,50 @@
+//go:build windows
+
+package logging
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+func TestNewEventLogSyncerEmptySource(t *testing.T) {
+	if _, err := NewEventLogSyncer(""); err == nil {
+		t.Error("NewEventLogSyncer succeeded with an empty source")
+	}
+}
+
+func TestNewEventLogSyncer(t *testing.T) {
+	source := fmt.Sprintf("uber-zap-demo-test-%d", os.Getpid())
+	ws, err := NewEventLogSyncer(source)
+	if err != nil {
+		// Registering a source needs administrator rights; without them the
+		// failure must come back as an error rather than a panic.
+		t.Skipf("cannot register an event source: %v", err)
+	}
+	t.Cleanup(func() {
+		ws.(interface{ Close() error }).Close()
+		eventlog.Remove(source)
+	})
+
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), ws, zapcore.DebugLevel))
+	for _, level := range []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel} {
+		if ce := logger.Check(level, "event log test"); ce != nil {
+			ce.Write()
+		}
+	}
+	if err := logger.Sync(); err != nil {
+		t.Errorf("Sync: %v", err)
+	}
+
+	// A source that is registered already is reused.
+	again, err := NewEventLogSyncer(source)
+	if err != nil {
+		t.Fatalf("NewEventLogSyncer for a registered source: %v", err)
+	}
+	again.(interface{ Close() error }).Close()
+}
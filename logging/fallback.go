@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// FallbackCore is the core returned by NewFallbackCore.
+type FallbackCore struct {
+	primary  zapcore.Core
+	fallback zapcore.Core
+	failures *atomic.Uint64
+}
+
+// NewFallbackCore returns a core that writes to primary and, whenever that
+// fails, for example because its disk is full, writes the same entry to
+// fallback instead, typically a core on standard error. Each failure is
+// counted, see Failures. Every entry tries primary first, so logging goes
+// back to it as soon as it recovers.
+//
+// The returned core is a *FallbackCore.
+func NewFallbackCore(primary, fallback zapcore.Core) zapcore.Core {
+	return &FallbackCore{primary: primary, fallback: fallback, failures: new(atomic.Uint64)}
+}
+
+// Failures returns how many entries primary failed to write, for this core
+// and every core derived from it with With.
+func (c *FallbackCore) Failures() uint64 {
+	return c.failures.Load()
+}
+
+func (c *FallbackCore) Enabled(level zapcore.Level) bool {
+	return c.primary.Enabled(level)
+}
+
+func (c *FallbackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &FallbackCore{
+		primary:  c.primary.With(fields),
+		fallback: c.fallback.With(fields),
+		failures: c.failures,
+	}
+}
+
+func (c *FallbackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *FallbackCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.primary.Write(ent, fields)
+	if err == nil {
+		return nil
+	}
+	c.failures.Add(1)
+	if !c.fallback.Enabled(ent.Level) {
+		return err
+	}
+	return c.fallback.Write(ent, fields)
+}
+
+// Sync syncs both cores, even if the first one fails.
+func (c *FallbackCore) Sync() error {
+	return multierr.Append(c.primary.Sync(), c.fallback.Sync())
+}
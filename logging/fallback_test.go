@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingSyncer is a fakeSyncer whose writes fail while failing is set, like
+// a file on a full disk.
+type failingSyncer struct {
+	*fakeSyncer
+	failing bool
+}
+
+func (s *failingSyncer) Write(p []byte) (int, error) {
+	if s.failing {
+		return 0, syscall.ENOSPC
+	}
+	return s.fakeSyncer.Write(p)
+}
+
+func TestNewFallbackCore(t *testing.T) {
+	primary := &failingSyncer{fakeSyncer: newFakeSyncer()}
+	fallback := newFakeSyncer()
+	core := NewFallbackCore(newSyncerLogger(primary).Core(), newSyncerLogger(fallback).Core())
+	logger := zap.New(core).With(zap.String("service", "api"))
+
+	logger.Info("first")
+	primary.failing = true
+	logger.Info("second")
+	logger.Info("third")
+	primary.failing = false
+	logger.Info("fourth")
+
+	tests := []struct {
+		name string
+		ws   *fakeSyncer
+		want []string
+	}{
+		{"primary", primary.fakeSyncer, []string{"first", "fourth"}},
+		{"fallback", fallback, []string{"second", "third"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(strings.TrimSpace(tt.ws.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got lines %q, want messages %v", lines, tt.want)
+			}
+			for i, line := range lines {
+				entry := decodeLine(t, line)
+				if entry["msg"] != tt.want[i] || entry["service"] != "api" {
+					t.Errorf("line %d = %v, want %q with the logger's fields", i, entry, tt.want[i])
+				}
+			}
+		})
+	}
+	if got := core.(*FallbackCore).Failures(); got != 2 {
+		t.Errorf("Failures = %d, want 2", got)
+	}
+}
+
+func TestFallbackCoreSync(t *testing.T) {
+	primary, fallback := newFakeSyncer(), newFakeSyncer()
+	primary.err = errors.New("primary sync failed")
+	core := NewFallbackCore(newSyncerLogger(primary).Core(), newSyncerLogger(fallback).Core())
+
+	if err := core.Sync(); !errors.Is(err, primary.err) {
+		t.Errorf("Sync = %v, want the primary's error", err)
+	}
+	if primary.syncCount() != 1 || fallback.syncCount() != 1 {
+		t.Errorf("synced primary %d and fallback %d times, want both once", primary.syncCount(), fallback.syncCount())
+	}
+}
+
+func TestFallbackCoreBothFail(t *testing.T) {
+	primary := &failingSyncer{fakeSyncer: newFakeSyncer(), failing: true}
+	fallback := &failingSyncer{fakeSyncer: newFakeSyncer(), failing: true}
+	core := NewFallbackCore(newSyncerLogger(primary).Core(), newSyncerLogger(fallback).Core())
+
+	if err := core.Write(zapcore.Entry{Message: "lost"}, nil); !errors.Is(err, syscall.ENOSPC) {
+		t.Errorf("Write = %v, want the fallback's error", err)
+	}
+}
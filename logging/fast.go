@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Fast is a narrower zap.Any: it dispatches the most common concrete types
+// (string, int, int64, float64, bool, time.Time, time.Duration, error and
+// []byte) straight to their typed constructors and only hands anything else
+// to zap.Any. A []byte is logged as base64 binary, like zap.Any does.
+//
+// It saves no allocations: zap.Any already switches on these types and
+// builds the same fields without allocating. What Fast skips is the rest of
+// zap.Any's much longer type switch, which BenchmarkFastInt puts at some
+// nanoseconds per field; both benchmarks report 0 allocs/op.
+func Fast(key string, val interface{}) zap.Field {
+	switch v := val.(type) {
+	case string:
+		return zap.String(key, v)
+	case int:
+		return zap.Int(key, v)
+	case int64:
+		return zap.Int64(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case time.Time:
+		return zap.Time(key, v)
+	case time.Duration:
+		return zap.Duration(key, v)
+	case error:
+		return zap.NamedError(key, v)
+	case []byte:
+		return zap.Binary(key, v)
+	default:
+		return zap.Any(key, val)
+	}
+}
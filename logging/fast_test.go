@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFast(t *testing.T) {
+	err := errors.New("connection refused")
+	now := time.Now()
+	type point struct{ X, Y int }
+	tests := []struct {
+		name string
+		val  interface{}
+		want zap.Field
+	}{
+		{"string", "marmotedu", zap.String("k", "marmotedu")},
+		{"int", 3, zap.Int("k", 3)},
+		{"int64", int64(3), zap.Int64("k", 3)},
+		{"float64", 1.5, zap.Float64("k", 1.5)},
+		{"bool", true, zap.Bool("k", true)},
+		{"time", now, zap.Time("k", now)},
+		{"duration", time.Second, zap.Duration("k", time.Second)},
+		{"error", err, zap.NamedError("k", err)},
+		{"bytes", []byte("raw"), zap.Binary("k", []byte("raw"))},
+		{"fallback", point{1, 2}, zap.Any("k", point{1, 2})},
+		{"fallback uint", uint8(7), zap.Uint8("k", 7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fast("k", tt.val)
+			if !got.Equals(tt.want) {
+				t.Errorf("Fast = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFastNilError(t *testing.T) {
+	var err error
+	if f := Fast("k", err); f.Type != zapcore.ReflectType {
+		t.Errorf("Type = %v, want a nil interface to fall back to zap.Any", f.Type)
+	}
+}
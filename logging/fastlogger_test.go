@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewFastLogger(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		wantCaller     bool
+		wantStacktrace bool
+	}{
+		{"fast", nil, false, false},
+		{"caller back on", []Option{WithZapOptions(zap.AddCaller())}, true, false},
+		{"stacktrace back on", []Option{WithStacktraceLevel(zapcore.ErrorLevel)}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.log")
+			logger, err := NewFastLogger(append([]Option{WithOutputPaths(path)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewFastLogger: %v", err)
+			}
+			logger.Error("fetch failed", zap.String("url", "https://example.com"))
+			logger.Sync()
+
+			entries := readEntries(t, path)
+			if len(entries) != 1 {
+				t.Fatalf("got %d JSON entries, want 1", len(entries))
+			}
+			e := entries[0]
+			if _, ok := e["caller"]; ok != tt.wantCaller {
+				t.Errorf("caller present = %v, want %v", ok, tt.wantCaller)
+			}
+			if _, ok := e["stacktrace"]; ok != tt.wantStacktrace {
+				t.Errorf("stacktrace present = %v, want %v", ok, tt.wantStacktrace)
+			}
+			if e["url"] != "https://example.com" {
+				t.Errorf("url = %v, want the field logged", e["url"])
+			}
+		})
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	logger, entries := newFileLogger(t, WithSampling(0, 0))
+	quiet := Quiet(logger)
+	tests := []struct {
+		name   string
+		logger *zap.Logger
+		quiet  bool
+	}{
+		{"quiet", quiet, true},
+		{"child of quiet", quiet.With(zap.String("lib", "noisy")), true},
+		{"parent", logger, false},
+		{"other child", logger.With(zap.String("component", "api")), false},
+	}
+	for _, tt := range tests {
+		tt.logger.Error(tt.name)
+	}
+
+	got := entries()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := got[i]
+			if e["msg"] != tt.name {
+				t.Fatalf("entry %d has msg %v, want %q", i, e["msg"], tt.name)
+			}
+			for _, key := range []string{"caller", "stacktrace"} {
+				if _, ok := e[key]; ok == tt.quiet {
+					t.Errorf("%s present = %v, want %v", key, ok, !tt.quiet)
+				}
+			}
+		})
+	}
+}
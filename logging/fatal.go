@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithFatalHook runs fn after a Fatal entry has been written and before the
+// process exits, giving cleanup that deferred functions would have done a
+// chance to run. When the option is given several times, the functions run
+// in reverse order, like deferred calls. The process still exits afterwards,
+// with status 1 or the one set with WithFatalExitCode. Entries below
+// FatalLevel never run the hooks.
+func WithFatalHook(fn func()) Option {
+	return func(o *options) {
+		o.fatalHooks = append(o.fatalHooks, fn)
+	}
+}
+
+// WithFatalExitCode makes Fatal entries exit the process with code instead
+// of 1, once the entry is written and the WithFatalHook functions have run.
+func WithFatalExitCode(code int) Option {
+	return func(o *options) {
+		o.fatalExitCode = code
+	}
+}
+
+// fatalHook returns the zapcore.CheckWriteHook that Build installs for Fatal
+// entries: it runs o's hooks and then exits with o's exit code.
+func (o *options) fatalHook() *fatalHook {
+	return &fatalHook{hooks: o.fatalHooks, then: exitHook(o.fatalExitCode)}
+}
+
+// fatalHook runs hooks, last first, once a Fatal entry is written, and then
+// hands the entry to then, which normally exits.
+type fatalHook struct {
+	hooks []func()
+	then  zapcore.CheckWriteHook
+}
+
+func (h *fatalHook) OnWrite(ce *zapcore.CheckedEntry, fields []zapcore.Field) {
+	for i := len(h.hooks) - 1; i >= 0; i-- {
+		h.hooks[i]()
+	}
+	h.then.OnWrite(ce, fields)
+}
+
+// exitHook is a zapcore.CheckWriteHook exiting the process with its value as
+// the status.
+type exitHook int
+
+func (code exitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	os.Exit(int(code))
+}
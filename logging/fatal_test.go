@@ -0,0 +1,168 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fatalLogger returns a logger whose Fatal entries run the hooks registered
+// by opts and are then handed to then instead of exiting the process.
+func fatalLogger(then zapcore.CheckWriteHook, opts ...Option) (*zap.Logger, *observer.ObservedLogs) {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	hook := o.fatalHook()
+	hook.then = then
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(core, zap.WithFatalHook(hook)), logs
+}
+
+func TestWithFatalHook(t *testing.T) {
+	tests := []struct {
+		level zapcore.Level
+		want  []int
+	}{
+		{zapcore.InfoLevel, nil},
+		{zapcore.WarnLevel, nil},
+		{zapcore.ErrorLevel, nil},
+		{zapcore.FatalLevel, []int{3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			var ran []int
+			var opts []Option
+			for i := 1; i <= 3; i++ {
+				i := i
+				opts = append(opts, WithFatalHook(func() { ran = append(ran, i) }))
+			}
+			logger, logs := fatalLogger(zapcore.WriteThenNoop, opts...)
+
+			logger.Log(tt.level, "shutting down")
+
+			if !reflect.DeepEqual(ran, tt.want) {
+				t.Errorf("hooks ran %v, want %v", ran, tt.want)
+			}
+			if logs.Len() != 1 {
+				t.Errorf("got %d entries, want the entry written before the hooks ran", logs.Len())
+			}
+		})
+	}
+}
+
+// recordingExit is a zapcore.CheckWriteHook standing in for exitHook: it
+// records the code the process would have exited with and returns.
+type recordingExit struct {
+	code int
+	ran  *[]string
+}
+
+func (r recordingExit) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	*r.ran = append(*r.ran, fmt.Sprintf("exit %d", r.code))
+}
+
+func TestWithFatalExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"default", nil, []string{"exit 1"}},
+		{"custom", []Option{WithFatalExitCode(70)}, []string{"exit 70"}},
+		{"after hooks", []Option{WithFatalExitCode(70), WithFatalHook(nil), WithFatalHook(nil)}, []string{"hook 2", "hook 1", "exit 70"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions()
+			for _, opt := range tt.opts {
+				opt(o)
+			}
+			var ran []string
+			for i := range o.fatalHooks {
+				i := i
+				o.fatalHooks[i] = func() { ran = append(ran, fmt.Sprintf("hook %d", i+1)) }
+			}
+			hook := o.fatalHook()
+			code, ok := hook.then.(exitHook)
+			if !ok {
+				t.Fatalf("Fatal entries end in %T, want an exitHook", hook.then)
+			}
+			hook.then = recordingExit{code: int(code), ran: &ran}
+			core, _ := observer.New(zapcore.DebugLevel)
+
+			zap.New(core, zap.WithFatalHook(hook)).Fatal("giving up")
+
+			if !reflect.DeepEqual(ran, tt.want) {
+				t.Errorf("ran %v, want %v", ran, tt.want)
+			}
+		})
+	}
+}
+
+const (
+	// fatalHelperEnv names the environment variable that makes
+	// TestFatalExitCode log a Fatal entry to the file it names and exit,
+	// rather than run the test.
+	fatalHelperEnv = "LOGGING_FATAL_HELPER"
+	// fatalCodeEnv, if set, is the WithFatalExitCode the helper uses.
+	fatalCodeEnv = "LOGGING_FATAL_CODE"
+)
+
+func TestFatalExitCode(t *testing.T) {
+	if path := os.Getenv(fatalHelperEnv); path != "" {
+		opts := []Option{WithOutputPaths(path), WithFatalHook(func() {
+			os.WriteFile(path+".hook", nil, 0o644)
+		})}
+		if code, err := strconv.Atoi(os.Getenv(fatalCodeEnv)); err == nil {
+			opts = append(opts, WithFatalExitCode(code))
+		}
+		logger, err := NewLogger(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger.Fatal("giving up")
+		t.Fatal("Fatal returned")
+	}
+
+	tests := []struct {
+		name string
+		// code is the WithFatalExitCode argument, if any.
+		code string
+		want int
+	}{
+		{"default", "", 1},
+		{"custom", "70", 70},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.log")
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFatalExitCode$")
+			cmd.Env = append(os.Environ(), fatalHelperEnv+"="+path, fatalCodeEnv+"="+tt.code)
+			out, err := cmd.CombinedOutput()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("helper process: %v, want it to exit with a status: %s", err, out)
+			}
+			if code := exitErr.ExitCode(); code != tt.want {
+				t.Errorf("exit code = %d, want %d: %s", code, tt.want, out)
+			}
+			if _, err := os.Stat(path + ".hook"); err != nil {
+				t.Errorf("hook did not run before the exit: %v", err)
+			}
+			if entries := readEntries(t, path); len(entries) != 1 || entries[0]["level"] != "fatal" {
+				t.Errorf("got entries %v, want the fatal entry", entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithFlattenedKeys writes every field at the top level of the entry, for
+// log stores that do not index nested JSON. Fields inside objects, whether
+// from zap.Object, zap.Namespace or reflected structs and maps, get the keys
+// of their parents as a prefix, joined with sep, "." if empty: the url field
+// in a "request" namespace becomes "request.url". Array elements are keyed
+// by their index, "ids.0", "ids.1", and so on at any depth. Empty objects
+// and arrays are kept as {} and [].
+//
+// When a flattened key is taken already, by another field or by one of the
+// entry's own keys like "msg", it is suffixed with "_2", "_3", ... so that
+// no value is lost.
+func WithFlattenedKeys(sep string) Option {
+	return func(o *options) {
+		if sep == "" {
+			sep = "."
+		}
+		o.flattenSep = sep
+	}
+}
+
+func newFlattenEncoder(enc zapcore.Encoder, cfg zapcore.EncoderConfig, sep string) zapcore.Encoder {
+	used := make(map[string]bool)
+	for _, key := range []string{cfg.TimeKey, cfg.LevelKey, cfg.NameKey, cfg.CallerKey, cfg.FunctionKey, cfg.MessageKey, cfg.StacktraceKey} {
+		if key != "" {
+			used[key] = true
+		}
+	}
+	return &flattenEncoder{enc: enc, sep: sep, used: used}
+}
+
+// flattenEncoder adds fields to the encoder it wraps under flattened keys.
+type flattenEncoder struct {
+	enc zapcore.Encoder
+	sep string
+	// prefix is put in front of every key: the parents of the object being
+	// added, and the namespaces opened so far.
+	prefix string
+	// used holds the keys added so far, to disambiguate collisions.
+	used map[string]bool
+}
+
+func (f *flattenEncoder) Clone() zapcore.Encoder {
+	used := make(map[string]bool, len(f.used))
+	for k := range f.used {
+		used[k] = true
+	}
+	return &flattenEncoder{enc: f.enc.Clone(), sep: f.sep, prefix: f.prefix, used: used}
+}
+
+func (f *flattenEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := f.Clone().(*flattenEncoder)
+	for _, field := range fields {
+		field.AddTo(final)
+	}
+	return final.enc.EncodeEntry(ent, nil)
+}
+
+// key returns the flattened key for key, unique within the entry.
+func (f *flattenEncoder) key(key string) string {
+	flat := f.prefix + key
+	unique := flat
+	for n := 2; f.used[unique]; n++ {
+		unique = flat + "_" + strconv.Itoa(n)
+	}
+	f.used[unique] = true
+	return unique
+}
+
+// nested runs add with key as a parent of the keys it adds.
+func (f *flattenEncoder) nested(key string, add func() error) error {
+	prefix := f.prefix
+	f.prefix = prefix + key + f.sep
+	defer func() { f.prefix = prefix }()
+	return add()
+}
+
+func (f *flattenEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	a := &flattenArray{f: f, key: key}
+	if err := arr.MarshalLogArray(a); err != nil {
+		return err
+	}
+	if a.n == 0 {
+		return f.enc.AddArray(f.key(key), emptyArray{})
+	}
+	return nil
+}
+
+func (f *flattenEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	before := len(f.used)
+	if err := f.nested(key, func() error { return obj.MarshalLogObject(f) }); err != nil {
+		return err
+	}
+	if len(f.used) == before {
+		return f.enc.AddObject(f.key(key), emptyObject{})
+	}
+	return nil
+}
+
+func (f *flattenEncoder) AddReflected(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return err
+	}
+	return f.addDecoded(key, decoded)
+}
+
+// addDecoded flattens a value decoded from JSON.
+func (f *flattenEncoder) addDecoded(key string, v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return f.enc.AddObject(f.key(key), emptyObject{})
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return f.nested(key, func() error {
+			for _, k := range keys {
+				if err := f.addDecoded(k, v[k]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case []interface{}:
+		if len(v) == 0 {
+			return f.enc.AddArray(f.key(key), emptyArray{})
+		}
+		return f.nested(key, func() error {
+			for i, elem := range v {
+				if err := f.addDecoded(strconv.Itoa(i), elem); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case string:
+		f.enc.AddString(f.key(key), v)
+		return nil
+	case bool:
+		f.enc.AddBool(f.key(key), v)
+		return nil
+	default:
+		// A json.Number or nil, which encode as they were.
+		return f.enc.AddReflected(f.key(key), v)
+	}
+}
+
+func (f *flattenEncoder) OpenNamespace(key string) {
+	f.prefix += key + f.sep
+}
+
+func (f *flattenEncoder) AddBinary(key string, v []byte)          { f.enc.AddBinary(f.key(key), v) }
+func (f *flattenEncoder) AddByteString(key string, v []byte)      { f.enc.AddByteString(f.key(key), v) }
+func (f *flattenEncoder) AddBool(key string, v bool)              { f.enc.AddBool(f.key(key), v) }
+func (f *flattenEncoder) AddComplex128(key string, v complex128)  { f.enc.AddComplex128(f.key(key), v) }
+func (f *flattenEncoder) AddComplex64(key string, v complex64)    { f.enc.AddComplex64(f.key(key), v) }
+func (f *flattenEncoder) AddDuration(key string, v time.Duration) { f.enc.AddDuration(f.key(key), v) }
+func (f *flattenEncoder) AddFloat64(key string, v float64)        { f.enc.AddFloat64(f.key(key), v) }
+func (f *flattenEncoder) AddFloat32(key string, v float32)        { f.enc.AddFloat32(f.key(key), v) }
+func (f *flattenEncoder) AddInt(key string, v int)                { f.enc.AddInt(f.key(key), v) }
+func (f *flattenEncoder) AddInt64(key string, v int64)            { f.enc.AddInt64(f.key(key), v) }
+func (f *flattenEncoder) AddInt32(key string, v int32)            { f.enc.AddInt32(f.key(key), v) }
+func (f *flattenEncoder) AddInt16(key string, v int16)            { f.enc.AddInt16(f.key(key), v) }
+func (f *flattenEncoder) AddInt8(key string, v int8)              { f.enc.AddInt8(f.key(key), v) }
+func (f *flattenEncoder) AddString(key, v string)                 { f.enc.AddString(f.key(key), v) }
+func (f *flattenEncoder) AddTime(key string, v time.Time)         { f.enc.AddTime(f.key(key), v) }
+func (f *flattenEncoder) AddUint(key string, v uint)              { f.enc.AddUint(f.key(key), v) }
+func (f *flattenEncoder) AddUint64(key string, v uint64)          { f.enc.AddUint64(f.key(key), v) }
+func (f *flattenEncoder) AddUint32(key string, v uint32)          { f.enc.AddUint32(f.key(key), v) }
+func (f *flattenEncoder) AddUint16(key string, v uint16)          { f.enc.AddUint16(f.key(key), v) }
+func (f *flattenEncoder) AddUint8(key string, v uint8)            { f.enc.AddUint8(f.key(key), v) }
+func (f *flattenEncoder) AddUintptr(key string, v uintptr)        { f.enc.AddUintptr(f.key(key), v) }
+
+// flattenArray adds the elements of the array key to f, keyed by index.
+type flattenArray struct {
+	f   *flattenEncoder
+	key string
+	// n counts the elements appended so far.
+	n int
+}
+
+// next returns the key of the next element, within the array's prefix.
+func (a *flattenArray) next() string {
+	i := a.n
+	a.n++
+	return strconv.Itoa(i)
+}
+
+// add runs add with the array's key as a parent.
+func (a *flattenArray) add(add func(key string) error) error {
+	key := a.next()
+	return a.f.nested(a.key, func() error { return add(key) })
+}
+
+// appendFunc adds a value that cannot fail.
+func (a *flattenArray) appendFunc(add func(key string)) {
+	_ = a.add(func(key string) error {
+		add(key)
+		return nil
+	})
+}
+
+func (a *flattenArray) AppendArray(arr zapcore.ArrayMarshaler) error {
+	return a.add(func(key string) error { return a.f.AddArray(key, arr) })
+}
+
+func (a *flattenArray) AppendObject(obj zapcore.ObjectMarshaler) error {
+	return a.add(func(key string) error { return a.f.AddObject(key, obj) })
+}
+
+func (a *flattenArray) AppendReflected(v interface{}) error {
+	return a.add(func(key string) error { return a.f.AddReflected(key, v) })
+}
+
+func (a *flattenArray) AppendBool(v bool) { a.appendFunc(func(k string) { a.f.AddBool(k, v) }) }
+func (a *flattenArray) AppendByteString(v []byte) {
+	a.appendFunc(func(k string) { a.f.AddByteString(k, v) })
+}
+func (a *flattenArray) AppendComplex128(v complex128) {
+	a.appendFunc(func(k string) { a.f.AddComplex128(k, v) })
+}
+func (a *flattenArray) AppendComplex64(v complex64) {
+	a.appendFunc(func(k string) { a.f.AddComplex64(k, v) })
+}
+func (a *flattenArray) AppendFloat64(v float64) {
+	a.appendFunc(func(k string) { a.f.AddFloat64(k, v) })
+}
+func (a *flattenArray) AppendFloat32(v float32) {
+	a.appendFunc(func(k string) { a.f.AddFloat32(k, v) })
+}
+func (a *flattenArray) AppendInt(v int)       { a.appendFunc(func(k string) { a.f.AddInt(k, v) }) }
+func (a *flattenArray) AppendInt64(v int64)   { a.appendFunc(func(k string) { a.f.AddInt64(k, v) }) }
+func (a *flattenArray) AppendInt32(v int32)   { a.appendFunc(func(k string) { a.f.AddInt32(k, v) }) }
+func (a *flattenArray) AppendInt16(v int16)   { a.appendFunc(func(k string) { a.f.AddInt16(k, v) }) }
+func (a *flattenArray) AppendInt8(v int8)     { a.appendFunc(func(k string) { a.f.AddInt8(k, v) }) }
+func (a *flattenArray) AppendString(v string) { a.appendFunc(func(k string) { a.f.AddString(k, v) }) }
+func (a *flattenArray) AppendUint(v uint)     { a.appendFunc(func(k string) { a.f.AddUint(k, v) }) }
+func (a *flattenArray) AppendUint64(v uint64) { a.appendFunc(func(k string) { a.f.AddUint64(k, v) }) }
+func (a *flattenArray) AppendUint32(v uint32) { a.appendFunc(func(k string) { a.f.AddUint32(k, v) }) }
+func (a *flattenArray) AppendUint16(v uint16) { a.appendFunc(func(k string) { a.f.AddUint16(k, v) }) }
+func (a *flattenArray) AppendUint8(v uint8)   { a.appendFunc(func(k string) { a.f.AddUint8(k, v) }) }
+func (a *flattenArray) AppendUintptr(v uintptr) {
+	a.appendFunc(func(k string) { a.f.AddUintptr(k, v) })
+}
+func (a *flattenArray) AppendDuration(v time.Duration) {
+	a.appendFunc(func(k string) { a.f.AddDuration(k, v) })
+}
+func (a *flattenArray) AppendTime(v time.Time) { a.appendFunc(func(k string) { a.f.AddTime(k, v) }) }
+
+type emptyArray struct{}
+
+func (emptyArray) MarshalLogArray(zapcore.ArrayEncoder) error { return nil }
+
+type emptyObject struct{}
+
+func (emptyObject) MarshalLogObject(zapcore.ObjectEncoder) error { return nil }
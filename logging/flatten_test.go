@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// flatUser is an ObjectMarshaler with a nested object.
+type flatUser struct {
+	name string
+	city string
+}
+
+func (u flatUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", u.name)
+	return enc.AddObject("address", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("city", u.city)
+		return nil
+	}))
+}
+
+func TestWithFlattenedKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		sep    string
+		fields []zap.Field
+		want   map[string]interface{}
+	}{
+		{
+			name:   "namespace",
+			fields: []zap.Field{zap.Namespace("request"), zap.String("url", "/orders"), zap.Int("status", 200)},
+			want:   map[string]interface{}{"request.url": "/orders", "request.status": 200.0},
+		},
+		{
+			name:   "nested objects",
+			fields: []zap.Field{zap.Object("user", flatUser{"frank", "Berlin"})},
+			want:   map[string]interface{}{"user.name": "frank", "user.address.city": "Berlin"},
+		},
+		{
+			name:   "array",
+			fields: []zap.Field{zap.Strings("ids", []string{"a", "b"})},
+			want:   map[string]interface{}{"ids.0": "a", "ids.1": "b"},
+		},
+		{
+			name: "array of objects",
+			fields: []zap.Field{zap.Array("users", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+				return enc.AppendObject(flatUser{"frank", "Berlin"})
+			}))},
+			want: map[string]interface{}{"users.0.name": "frank", "users.0.address.city": "Berlin"},
+		},
+		{
+			name:   "reflected",
+			fields: []zap.Field{zap.Any("meta", map[string]interface{}{"tags": []string{"x"}, "limits": map[string]int{"rps": 10}})},
+			want:   map[string]interface{}{"meta.tags.0": "x", "meta.limits.rps": 10.0},
+		},
+		{
+			name: "empty",
+			fields: []zap.Field{
+				zap.Strings("none", nil),
+				zap.Object("nothing", zapcore.ObjectMarshalerFunc(func(zapcore.ObjectEncoder) error { return nil })),
+			},
+			want: map[string]interface{}{"none": []interface{}{}, "nothing": map[string]interface{}{}},
+		},
+		{
+			name: "collisions",
+			fields: []zap.Field{
+				zap.String("user.name", "flat"),
+				zap.Object("user", flatUser{"nested", "Berlin"}),
+				zap.String("level", "mine"),
+			},
+			want: map[string]interface{}{
+				"user.name":         "flat",
+				"user.name_2":       "nested",
+				"user.address.city": "Berlin",
+				"level_2":           "mine",
+			},
+		},
+		{
+			name:   "custom separator",
+			sep:    "_",
+			fields: []zap.Field{zap.Namespace("request"), zap.Strings("ids", []string{"a"})},
+			want:   map[string]interface{}{"request_ids_0": "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithFlattenedKeys(tt.sep))
+			logger.Info("flattened", tt.fields...)
+
+			got := entries()[0]
+			for _, key := range []string{"level", "ts", "caller", "msg"} {
+				delete(got, key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFlattenedKeysContext(t *testing.T) {
+	logger, entries := newFileLogger(t, WithFlattenedKeys(""))
+	child := logger.With(zap.Namespace("request"), zap.String("id", "42"))
+	child.Info("first", zap.String("url", "/orders"))
+	// The child's keys are not taken by its earlier entries.
+	child.Info("second", zap.String("url", "/users"))
+
+	tests := []struct {
+		key  string
+		want []interface{}
+	}{
+		{"request.id", []interface{}{"42", "42"}},
+		{"request.url", []interface{}{"/orders", "/users"}},
+	}
+	got := entries()
+	for _, tt := range tests {
+		for i, e := range got {
+			if e[tt.key] != tt.want[i] {
+				t.Errorf("entry %d: %s = %v, want %v", i, tt.key, e[tt.key], tt.want[i])
+			}
+		}
+	}
+}
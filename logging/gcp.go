@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewGCPEncoderConfig returns an encoder config for Google Cloud Logging,
+// which reads the entry's severity from "severity" and its time from
+// "timestamp". Levels are encoded with GCPLevelEncoder. Use it with a JSON
+// encoder.
+func NewGCPEncoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.LevelKey = "severity"
+	cfg.MessageKey = "message"
+	cfg.TimeKey = "timestamp"
+	cfg.EncodeLevel = GCPLevelEncoder
+	cfg.EncodeTime = zapcore.TimeEncoderOfLayout(time.RFC3339Nano)
+	return cfg
+}
+
+// GCPLevelEncoder encodes levels as Cloud Logging severities: DEBUG, INFO,
+// WARNING and ERROR, with DPanic, Panic and Fatal all reported as CRITICAL.
+func GCPLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch l {
+	case zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel:
+		enc.AppendString("CRITICAL")
+	default:
+		enc.AppendString("DEFAULT")
+	}
+}
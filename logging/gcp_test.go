@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGCPEncoderConfig(t *testing.T) {
+	tests := []struct {
+		level zapcore.Level
+		want  string
+	}{
+		{zapcore.DebugLevel, "DEBUG"},
+		{zapcore.InfoLevel, "INFO"},
+		{zapcore.WarnLevel, "WARNING"},
+		{zapcore.ErrorLevel, "ERROR"},
+		{zapcore.DPanicLevel, "CRITICAL"},
+		{zapcore.PanicLevel, "CRITICAL"},
+		{zapcore.FatalLevel, "CRITICAL"},
+		{zapcore.Level(42), "DEFAULT"},
+	}
+	enc := zapcore.NewJSONEncoder(NewGCPEncoderConfig())
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			buf, err := enc.EncodeEntry(zapcore.Entry{Level: tt.level, Time: testEntryTime, Message: "hi"}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("%s: %v", buf, err)
+			}
+			want := map[string]interface{}{
+				"severity":  tt.want,
+				"message":   "hi",
+				"timestamp": "2023-10-24T11:06:18Z",
+			}
+			for key, v := range want {
+				if doc[key] != v {
+					t.Errorf("%s = %v, want %v", key, doc[key], v)
+				}
+			}
+		})
+	}
+}
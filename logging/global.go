@@ -0,0 +1,19 @@
+package logging
+
+import "go.uber.org/zap"
+
+// InitGlobal builds a logger with NewLogger and installs it as zap's global
+// logger, so that zap.L() and zap.S() use it from then on.
+//
+// The returned flush syncs the global logger and is meant to be deferred in
+// main. It can be called any number of times.
+func InitGlobal(opts ...Option) (flush func(), err error) {
+	logger, err := NewLogger(opts...)
+	if err != nil {
+		return nil, err
+	}
+	zap.ReplaceGlobals(logger)
+	return func() {
+		_ = logger.Sync()
+	}, nil
+}
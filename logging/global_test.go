@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestInitGlobal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "global.log")
+	previous := zap.L()
+	defer zap.ReplaceGlobals(previous)
+
+	flush, err := InitGlobal(WithOutputPaths(path))
+	if err != nil {
+		t.Fatalf("InitGlobal: %v", err)
+	}
+	if zap.L() == previous {
+		t.Fatal("InitGlobal did not replace the global logger")
+	}
+	zap.L().Info("from L")
+	zap.S().Infow("from S", "attempt", 3)
+	flush()
+	flush()
+
+	entries := readEntries(t, path)
+	tests := []struct {
+		msg string
+	}{
+		{"from L"},
+		{"from S"},
+	}
+	if len(entries) != len(tests) {
+		t.Fatalf("got %d entries, want %d: %v", len(entries), len(tests), entries)
+	}
+	for i, tt := range tests {
+		if entries[i]["msg"] != tt.msg {
+			t.Errorf("entry %d msg = %v, want %q", i, entries[i]["msg"], tt.msg)
+		}
+		ts, _ := entries[i]["ts"].(string)
+		if _, err := time.Parse(time.RFC3339, ts); err != nil {
+			t.Errorf("entry %d ts %q is not RFC3339", i, entries[i]["ts"])
+		}
+	}
+}
+
+func TestInitGlobalError(t *testing.T) {
+	previous := zap.L()
+	flush, err := InitGlobal(WithEncoding("xml"))
+	if err == nil {
+		t.Fatal("InitGlobal succeeded with an unknown encoding")
+	}
+	if flush != nil {
+		t.Error("InitGlobal returned a flush function along with its error")
+	}
+	if zap.L() != previous {
+		t.Error("a failed InitGlobal replaced the global logger")
+	}
+}
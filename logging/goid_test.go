@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestWithGoroutineID(t *testing.T) {
+	logger, entries := newFileLogger(t, WithSampling(0, 0), WithGoroutineID())
+	const workers = 8
+	ids := make([]int64, workers)
+	var logged, done sync.WaitGroup
+	logged.Add(workers)
+	done.Add(workers)
+	for w := 0; w < workers; w++ {
+		w := w
+		go func() {
+			defer done.Done()
+			ids[w] = goroutineID()
+			logger.Info(fmt.Sprint("worker ", w))
+			logger.With().Info(fmt.Sprint("worker ", w))
+			// Keep every worker alive until all have logged, so that none of
+			// their IDs is reused.
+			logged.Done()
+			logged.Wait()
+		}()
+	}
+	done.Wait()
+
+	want := make(map[string]float64, workers)
+	seen := make(map[int64]bool, workers)
+	for w, id := range ids {
+		if id == 0 || seen[id] {
+			t.Fatalf("worker %d has goroutine ID %d, want a distinct, non-zero one", w, id)
+		}
+		seen[id] = true
+		want[fmt.Sprint("worker ", w)] = float64(id)
+	}
+	got := entries()
+	if len(got) != 2*workers {
+		t.Fatalf("got %d entries, want %d", len(got), 2*workers)
+	}
+	for _, e := range got {
+		msg := e["msg"].(string)
+		if e["goid"] != want[msg] {
+			t.Errorf("%s: goid = %v, want %v", msg, e["goid"], want[msg])
+		}
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	own := goroutineID()
+	other := make(chan int64)
+	go func() { other <- goroutineID() }()
+
+	tests := []struct {
+		name string
+		id   int64
+	}{
+		{"test goroutine", own},
+		{"other goroutine", <-other},
+	}
+	for _, tt := range tests {
+		if tt.id <= 0 {
+			t.Errorf("%s: goroutineID = %d, want a positive ID", tt.name, tt.id)
+		}
+	}
+	if tests[0].id == tests[1].id {
+		t.Errorf("both goroutines have ID %d", own)
+	}
+}
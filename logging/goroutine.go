@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Go runs fn in a new goroutine. If fn panics, the panic is recovered and
+// logged through l at ErrorLevel, with the value under "panic" and the
+// panicking goroutine's stack, from runtime/debug.Stack, under "stacktrace";
+// the goroutine then returns instead of crashing the program. A fn that
+// returns normally logs nothing.
+//
+// The entry's caller is the call to Go, since the panic is logged from the
+// new goroutine, whose own stack leads back to nothing of the caller's.
+func Go(l *zap.Logger, fn func()) {
+	pc, file, line, ok := runtime.Caller(1)
+	site := zapcore.EntryCaller{Defined: ok, PC: pc, File: file, Line: line}
+	if f := runtime.FuncForPC(pc); f != nil {
+		site.Function = f.Name()
+	}
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				// The stack below already shows where the panic happened,
+				// so the logger's own one, taken here, is left out.
+				ce := withoutStacktrace(l).Check(zapcore.ErrorLevel, "goroutine panicked")
+				if ce == nil {
+					return
+				}
+				if ce.Caller.Defined {
+					ce.Caller = site
+				}
+				ce.Write(
+					zap.Any("panic", p),
+					zap.ByteString("stacktrace", debug.Stack()),
+				)
+			}
+		}()
+		fn()
+	}()
+}
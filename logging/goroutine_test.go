@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+// waitForEntries waits up to a second for logs to hold n entries.
+func waitForEntries(t *testing.T, logs *observer.ObservedLogs, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for logs.Len() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d entries, want %d", logs.Len(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGoPanic(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core, zap.AddCaller())
+
+	want := here(1)
+	Go(logger, func() { panic("boom") })
+	waitForEntries(t, logs, 1)
+
+	logtest.AssertLogged(t, logs, zapcore.ErrorLevel, "goroutine panicked")
+	entry := logs.All()[0]
+	if got, _ := logtest.FieldValue(entry, "panic"); got != "boom" {
+		t.Errorf("panic = %v, want boom", got)
+	}
+	stack, _ := logtest.FieldValue(entry, "stacktrace")
+	if s, _ := stack.(string); !strings.Contains(s, "TestGoPanic") {
+		t.Errorf("stacktrace %q does not show the panicking function", stack)
+	}
+	if got := fmt.Sprintf("%s:%d", filepath.Base(entry.Caller.File), entry.Caller.Line); got != want {
+		t.Errorf("caller = %s, want the call to Go at %s", got, want)
+	}
+}
+
+func TestGoNoPanic(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	done := make(chan struct{})
+	Go(logger, func() { close(done) })
+	<-done
+	// Give the goroutine time to log, were it going to.
+	time.Sleep(10 * time.Millisecond)
+
+	if logs.Len() != 0 {
+		t.Errorf("got %d entries, want none for a function that returns", logs.Len())
+	}
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor logs one entry per unary RPC with the method, the
+// duration, the gRPC status code, the peer address and the x-request-id
+// metadata if the client sent one. Calls that fail with a code other than OK
+// are logged at ErrorLevel, the rest at InfoLevel.
+func UnaryServerInterceptor(l *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, l, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor; it logs once the stream handler has returned.
+func StreamServerInterceptor(l *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logRPC(ss.Context(), l, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logRPC(ctx context.Context, l *zap.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("grpc.method", method),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("grpc.code", code.String()),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer.address", p.Addr.String()))
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get("x-request-id"); len(ids) > 0 {
+			fields = append(fields, zap.String("request_id", ids[0]))
+		}
+	}
+
+	if code != codes.OK {
+		l.Error("grpc call", append(fields, zap.Error(err))...)
+		return
+	}
+	l.Info("grpc call", fields...)
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+// streamDesc describes a streaming method whose handler fails with
+// codes.Unavailable.
+var streamDesc = grpc.ServiceDesc{
+	ServiceName: "demo.Stream",
+	HandlerType: (*interface{})(nil),
+	Streams: []grpc.StreamDesc{{
+		StreamName: "Watch",
+		Handler: func(interface{}, grpc.ServerStream) error {
+			return status.Error(codes.Unavailable, "try later")
+		},
+		ServerStreams: true,
+	}},
+}
+
+func TestServerInterceptors(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(logger)),
+		grpc.StreamInterceptor(StreamServerInterceptor(logger)),
+	)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	srv.RegisterService(&streamDesc, struct{}{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	tests := []struct {
+		name       string
+		call       func(ctx context.Context) error
+		wantMethod string
+		wantCode   string
+		wantLevel  zapcore.Level
+	}{
+		{
+			name: "unary ok",
+			call: func(ctx context.Context) error {
+				_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+				return err
+			},
+			wantMethod: "/grpc.health.v1.Health/Check",
+			wantCode:   "OK",
+			wantLevel:  zapcore.InfoLevel,
+		},
+		{
+			name: "unary failing",
+			call: func(ctx context.Context) error {
+				_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"})
+				return err
+			},
+			wantMethod: "/grpc.health.v1.Health/Check",
+			wantCode:   "NotFound",
+			wantLevel:  zapcore.ErrorLevel,
+		},
+		{
+			name: "stream failing",
+			call: func(ctx context.Context) error {
+				stream, err := conn.NewStream(ctx, &streamDesc.Streams[0], "/demo.Stream/Watch")
+				if err != nil {
+					return err
+				}
+				if err := stream.CloseSend(); err != nil {
+					return err
+				}
+				return stream.RecvMsg(&healthpb.HealthCheckResponse{})
+			},
+			wantMethod: "/demo.Stream/Watch",
+			wantCode:   "Unavailable",
+			wantLevel:  zapcore.ErrorLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.TakeAll()
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "abc")
+			err := tt.call(ctx)
+			if got := status.Code(err).String(); got != tt.wantCode {
+				t.Fatalf("call returned %v, want code %s", err, tt.wantCode)
+			}
+
+			logtest.AssertLogged(t, logs, tt.wantLevel, "grpc call")
+			entry := logs.All()[0]
+			want := map[string]interface{}{
+				"grpc.method":  tt.wantMethod,
+				"grpc.code":    tt.wantCode,
+				"request_id":   "abc",
+				"peer.address": "bufconn",
+			}
+			for key, v := range want {
+				if got, _ := logtest.FieldValue(entry, key); got != v {
+					t.Errorf("%s = %v, want %v", key, got, v)
+				}
+			}
+			_, hasErr := logtest.FieldValue(entry, "error")
+			if hasErr != (tt.wantCode != "OK") {
+				t.Errorf("error field present = %v for code %s", hasErr, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultHeartbeatInterval replaces a non-positive StartHeartbeat interval.
+const defaultHeartbeatInterval = time.Minute
+
+// StartHeartbeat logs a "heartbeat" InfoLevel entry through l every
+// interval, a minute if interval is not positive, so that a long-running
+// daemon shows it is alive along with a few health figures: the number of
+// goroutines as "goroutines", the bytes of allocated heap objects as
+// "heap_alloc" and the number of completed GC cycles as "gc_count".
+//
+// runtime.ReadMemStats stops the world, so it is called once per heartbeat
+// and never more often; nothing is logged before the first interval has
+// passed.
+//
+// The returned stop ends the heartbeats and waits for the goroutine sending
+// them to exit, so no heartbeat is logged after it returns. It is safe to
+// call more than once.
+func StartHeartbeat(l *zap.Logger, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var stats runtime.MemStats
+		for {
+			select {
+			case <-ticker.C:
+				runtime.ReadMemStats(&stats)
+				l.Info("heartbeat",
+					zap.Int("goroutines", runtime.NumGoroutine()),
+					zap.Uint64("heap_alloc", stats.HeapAlloc),
+					zap.Uint32("gc_count", stats.NumGC),
+				)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestStartHeartbeat(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	stop := StartHeartbeat(zap.New(core), 5*time.Millisecond)
+	waitForEntries(t, logs, 2)
+	stop()
+	n := logs.Len()
+	time.Sleep(25 * time.Millisecond)
+
+	if logs.Len() != n {
+		t.Errorf("got %d heartbeats after stop, want none", logs.Len()-n)
+	}
+	logtest.AssertLogged(t, logs, zapcore.InfoLevel, "heartbeat")
+	fields := logs.All()[0].ContextMap()
+	tests := []struct {
+		key   string
+		valid func(v interface{}) bool
+	}{
+		{"goroutines", func(v interface{}) bool { n, ok := v.(int64); return ok && n > 0 }},
+		{"heap_alloc", func(v interface{}) bool { n, ok := v.(uint64); return ok && n > 0 }},
+		{"gc_count", func(v interface{}) bool { _, ok := v.(uint32); return ok }},
+	}
+	for _, tt := range tests {
+		if !tt.valid(fields[tt.key]) {
+			t.Errorf("%s = %#v, want a plausible figure", tt.key, fields[tt.key])
+		}
+	}
+
+	// Stopping again is harmless.
+	stop()
+}
+
+func TestStartHeartbeatFirstInterval(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	stop := StartHeartbeat(zap.New(core), time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	if logs.Len() != 0 {
+		t.Errorf("got %d heartbeats, want none before the first interval", logs.Len())
+	}
+}
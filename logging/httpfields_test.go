@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPRequestFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func() *http.Request
+		want    map[string]interface{}
+	}{
+		{
+			name: "full",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "http://api.example.com/orders?page=2&sort=desc", strings.NewReader(`{"id":1}`))
+				r.RemoteAddr = "10.0.0.7:51234"
+				r.Header.Set("User-Agent", "curl/8.4.0")
+				r.Header.Set("Authorization", "Bearer secret")
+				r.Header.Set("Cookie", "session=secret")
+				return r
+			},
+			want: map[string]interface{}{
+				"method":         "POST",
+				"path":           "/orders",
+				"query":          "page=2&sort=desc",
+				"remote_addr":    "10.0.0.7:51234",
+				"user_agent":     "curl/8.4.0",
+				"host":           "api.example.com",
+				"content_length": int64(8),
+			},
+		},
+		{
+			name: "no query",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			},
+			want: map[string]interface{}{
+				"method":         "GET",
+				"path":           "/healthz",
+				"query":          "",
+				"remote_addr":    "192.0.2.1:1234",
+				"user_agent":     "",
+				"host":           "example.com",
+				"content_length": int64(0),
+			},
+		},
+		{
+			name: "no URL, unknown length",
+			request: func() *http.Request {
+				return &http.Request{Method: http.MethodPut, ContentLength: -1}
+			},
+			want: map[string]interface{}{
+				"method":         "PUT",
+				"path":           "",
+				"query":          "",
+				"remote_addr":    "",
+				"user_agent":     "",
+				"host":           "",
+				"content_length": int64(-1),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			for _, f := range HTTPRequestFields(tt.request()) {
+				f.AddTo(enc)
+			}
+			if !reflect.DeepEqual(enc.Fields, tt.want) {
+				t.Errorf("fields = %v, want %v", enc.Fields, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// httpQueueBatches is how many batches may wait to be posted before the
+	// syncer starts dropping lines.
+	httpQueueBatches = 8
+	// httpRetryBackoff is the wait before the first retry of a failed post;
+	// it doubles with every further retry, up to httpMaxBackoff.
+	httpRetryBackoff = 100 * time.Millisecond
+	httpMaxBackoff   = 10 * time.Second
+)
+
+var errHTTPSyncerClosed = errors.New("logging: http syncer is closed")
+
+// HTTPSyncer is the WriteSyncer returned by NewHTTPSyncer.
+type HTTPSyncer struct {
+	url        string
+	maxRetries int
+	client     *http.Client
+	batch      *batcher[[]byte]
+}
+
+// NewHTTPSyncer returns a WriteSyncer that POSTs the written lines to
+// endpoint as newline-delimited JSON, in batches of up to batchSize lines, or
+// whatever has accumulated every flushInterval, and immediately on Sync.
+//
+// A batch the server fails to accept, with a network error or a non-2xx
+// status, is retried up to maxRetries times, waiting 100ms before the first
+// retry and twice as long before each one after that. When the retries run
+// out the batch is dropped. Writes never block: lines are also dropped when
+// the queue is full. Dropped lines are counted, see Dropped.
+//
+// The returned function writes what is still queued and stops the
+// background worker. The returned syncer is a *HTTPSyncer.
+func NewHTTPSyncer(endpoint string, batchSize int, flushInterval time.Duration, maxRetries int) (zapcore.WriteSyncer, func()) {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	s := &HTTPSyncer{
+		url:        endpoint,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+	s.batch = newBatcher(batchSize, httpQueueBatches, flushInterval, errHTTPSyncerClosed, s.postLines)
+	return s, func() { _ = s.batch.close() }
+}
+
+// Write queues one encoded entry. It never fails: a full queue drops the line.
+func (s *HTTPSyncer) Write(p []byte) (int, error) {
+	line := make([]byte, len(p))
+	copy(line, p)
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+	s.batch.add(line)
+	return len(p), nil
+}
+
+// Sync posts every queued line and reports whether the server accepted them.
+func (s *HTTPSyncer) Sync() error {
+	return s.batch.sync()
+}
+
+// Dropped returns the number of lines that never made it to the server.
+func (s *HTTPSyncer) Dropped() int64 {
+	return s.batch.dropped.Load()
+}
+
+func (s *HTTPSyncer) postLines(lines [][]byte) error {
+	return s.post(bytes.Join(lines, nil))
+}
+
+// post sends body, retrying with exponential backoff. A close of the syncer
+// cuts the backoff short and gives up on the batch.
+func (s *HTTPSyncer) post(body []byte) error {
+	backoff := httpRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := s.postOnce(body)
+		if err == nil || attempt >= s.maxRetries {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.batch.done:
+			return err
+		}
+		if backoff *= 2; backoff > httpMaxBackoff {
+			backoff = httpMaxBackoff
+		}
+	}
+}
+
+func (s *HTTPSyncer) postOnce(body []byte) error {
+	resp, err := s.client.Post(s.url, "application/x-ndjson", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("logging: http post: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("logging: http post: unexpected status %s", resp.Status)
+	}
+	return nil
+}
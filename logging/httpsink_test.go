@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector is an HTTP log collector that fails the first failures requests
+// with 503 and records the bodies of the others.
+type collector struct {
+	*httptest.Server
+	failures int
+
+	mu       sync.Mutex
+	attempts int
+	bodies   []string
+}
+
+func newCollector(t *testing.T, failures int) *collector {
+	c := &collector{failures: failures}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+		}
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.attempts++
+		if c.attempts <= c.failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		c.bodies = append(c.bodies, string(body))
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// received returns the attempts made and the bodies accepted so far.
+func (c *collector) received() (int, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.attempts, append([]string(nil), c.bodies...)
+}
+
+func TestHTTPSyncerRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		maxRetries   int
+		wantErr      bool
+		wantAttempts int
+		wantDropped  int64
+	}{
+		{"first attempt fails", 1, 3, false, 2, 0},
+		{"retries exhausted", 100, 2, true, 3, 3},
+		{"no retries", 1, 0, true, 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newCollector(t, tt.failures)
+			ws, stop := NewHTTPSyncer(srv.URL, 10, time.Hour, tt.maxRetries)
+			defer stop()
+			logger := newSyncerLogger(ws)
+			for i := 0; i < 3; i++ {
+				logger.Info(fmt.Sprint(i))
+			}
+
+			err := logger.Sync()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Sync = %v, want error: %v", err, tt.wantErr)
+			}
+			attempts, bodies := srv.received()
+			if attempts != tt.wantAttempts {
+				t.Errorf("server got %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+			if got := ws.(*HTTPSyncer).Dropped(); got != tt.wantDropped {
+				t.Errorf("Dropped = %d, want %d", got, tt.wantDropped)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(bodies) != 1 || strings.Count(bodies[0], "\n") != 3 {
+				t.Fatalf("got bodies %q, want one batch of 3 lines", bodies)
+			}
+			for i, line := range strings.Split(strings.TrimSpace(bodies[0]), "\n") {
+				if msg := decodeLine(t, line)["msg"]; msg != fmt.Sprint(i) {
+					t.Errorf("line %d has msg %v, want %d", i, msg, i)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPSyncerBatches(t *testing.T) {
+	srv := newCollector(t, 0)
+	ws, stop := NewHTTPSyncer(srv.URL, 2, time.Hour, 0)
+	logger := newSyncerLogger(ws)
+	for i := 0; i < 5; i++ {
+		logger.Info(fmt.Sprint(i))
+	}
+	stop()
+
+	_, bodies := srv.received()
+	var sizes []int
+	for _, b := range bodies {
+		sizes = append(sizes, strings.Count(b, "\n"))
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Errorf("got batches of %v lines, want [2 2 1]", sizes)
+	}
+	if err := ws.Sync(); !errors.Is(err, errHTTPSyncerClosed) {
+		t.Errorf("Sync after stop = %v, want errHTTPSyncerClosed", err)
+	}
+}
+
+func TestHTTPSyncerFlushInterval(t *testing.T) {
+	srv := newCollector(t, 0)
+	ws, stop := NewHTTPSyncer(srv.URL, 100, 10*time.Millisecond, 0)
+	defer stop()
+	newSyncerLogger(ws).Info("tick")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, bodies := srv.received(); len(bodies) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the partial batch was not posted after the flush interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// keyedSamplerTick is the window each key's entry count covers.
+	keyedSamplerTick = time.Second
+	// keyedSamplerSweepInterval is how often idle keys are looked for.
+	keyedSamplerSweepInterval = time.Minute
+)
+
+// WithKeyedSampler samples entries per value of the keyField field, such as
+// a tenant ID, so that each value's log volume is capped on its own. It
+// samples like zapcore's sampler, with perKeyRate as both its first and its
+// thereafter argument, the way zap's production config uses 100 for both:
+// of the entries carrying a value within a second of entry time, the first
+// perKeyRate are written, and after that every perKeyRate-th one. The field
+// may be passed to the log call or attached earlier with With. Entries
+// without the field are not sampled here; the logger's own sampling, see
+// WithSampling, still applies to them and to everything else.
+//
+// zapcore's sampler is not used per key because each one preallocates its
+// counters, hundreds of kilobytes, and here it would be one per tenant.
+// Instead a key only costs a counter for the current second, and keys idle
+// for a whole second are forgotten, which keeps memory bounded when key
+// values are unbounded.
+//
+// Build fails unless perKeyRate is positive.
+func WithKeyedSampler(keyField string, perKeyRate int) Option {
+	return func(o *options) {
+		if perKeyRate < 1 {
+			o.setErr(fmt.Errorf("logging: keyed sampler rate must be positive, got %d", perKeyRate))
+			return
+		}
+		s := &keyedSampler{
+			first:      perKeyRate,
+			thereafter: perKeyRate,
+			windows:    make(map[string]*keyedWindow),
+		}
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &keyedSamplerCore{Core: core, keyField: keyField, sampler: s}
+		})
+	}
+}
+
+type keyedSampler struct {
+	first, thereafter int
+
+	mu        sync.Mutex
+	windows   map[string]*keyedWindow
+	lastSweep time.Time
+}
+
+// keyedWindow counts one key's entries in the tick starting at start.
+type keyedWindow struct {
+	start time.Time
+	count int
+}
+
+func (s *keyedSampler) allow(key string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= keyedSamplerSweepInterval {
+		for key, w := range s.windows {
+			if now.Sub(w.start) >= keyedSamplerTick {
+				delete(s.windows, key)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok {
+		w = &keyedWindow{start: now}
+		s.windows[key] = w
+	} else if now.Sub(w.start) >= keyedSamplerTick {
+		w.start, w.count = now, 0
+	}
+	w.count++
+	if w.count <= s.first {
+		return true
+	}
+	return (w.count-s.first)%s.thereafter == 0
+}
+
+type keyedSamplerCore struct {
+	zapcore.Core
+	keyField string
+	sampler  *keyedSampler
+	// key is the value of keyField when it was attached with With.
+	key    string
+	hasKey bool
+}
+
+func (c *keyedSamplerCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	if key, ok := fieldValueString(fields, c.keyField); ok {
+		clone.key, clone.hasKey = key, true
+	}
+	return &clone
+}
+
+func (c *keyedSamplerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *keyedSamplerCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key, ok := fieldValueString(fields, c.keyField)
+	if !ok {
+		key, ok = c.key, c.hasKey
+	}
+	if ok && !c.sampler.allow(key, ent.Time) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
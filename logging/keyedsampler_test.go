@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWithKeyedSampler(t *testing.T) {
+	logger, entries := newFileLogger(t, WithSampling(0, 0), WithKeyedSampler("tenant", 10))
+	light := logger.With(zap.String("tenant", "light"))
+	for i := 0; i < 500; i++ {
+		logger.Info("request", zap.String("tenant", "heavy"))
+		if i < 5 {
+			light.Info("request")
+		}
+		if i < 50 {
+			logger.Info("untagged")
+		}
+	}
+
+	counts := make(map[interface{}]int)
+	for _, e := range entries() {
+		if e["msg"] == "untagged" {
+			counts["untagged"]++
+			continue
+		}
+		counts[e["tenant"]]++
+	}
+	tests := []struct {
+		key  string
+		want int
+	}{
+		// The first 10, then every 10th of the remaining 490.
+		{"heavy", 10 + 49},
+		{"light", 5},
+		{"untagged", 50},
+	}
+	for _, tt := range tests {
+		if counts[tt.key] != tt.want {
+			t.Errorf("%s: got %d entries, want %d", tt.key, counts[tt.key], tt.want)
+		}
+	}
+}
+
+func TestKeyedSamplerAllow(t *testing.T) {
+	start := time.Date(2023, 10, 24, 11, 6, 18, 0, time.UTC)
+	tests := []struct {
+		name string
+		// at is when each entry is logged, relative to start.
+		at   []time.Duration
+		want []bool
+	}{
+		{
+			name: "first then thereafter",
+			at:   []time.Duration{0, 0, 0, 0, 0, 0},
+			want: []bool{true, true, false, true, false, true},
+		},
+		{
+			name: "new tick resets the count",
+			at:   []time.Duration{0, 0, 0, time.Second, time.Second},
+			want: []bool{true, true, false, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &keyedSampler{first: 2, thereafter: 2, windows: make(map[string]*keyedWindow)}
+			for i, d := range tt.at {
+				if got := s.allow("tenant", start.Add(d)); got != tt.want[i] {
+					t.Errorf("entry %d: allow = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKeyedSamplerEvictsIdleKeys(t *testing.T) {
+	start := time.Date(2023, 10, 24, 11, 6, 18, 0, time.UTC)
+	s := &keyedSampler{first: 1, thereafter: 1, windows: make(map[string]*keyedWindow)}
+	for i := 0; i < 1000; i++ {
+		s.allow(fmt.Sprint(i), start)
+	}
+	s.allow("active", start.Add(keyedSamplerSweepInterval))
+
+	if len(s.windows) != 1 {
+		t.Errorf("sampler keeps %d keys, want only the active one", len(s.windows))
+	}
+}
+
+func TestWithKeyedSamplerRate(t *testing.T) {
+	if _, err := NewLogger(WithKeyedSampler("tenant", 0)); err == nil {
+		t.Error("NewLogger succeeded with a zero rate")
+	}
+}
@@ -0,0 +1,23 @@
+package logging
+
+// WithMessageKey renames the "msg" key. An empty key leaves the message out.
+func WithMessageKey(key string) Option {
+	return func(o *options) {
+		o.config.EncoderConfig.MessageKey = key
+	}
+}
+
+// WithLevelKey renames the "level" key. An empty key leaves the level out.
+func WithLevelKey(key string) Option {
+	return func(o *options) {
+		o.config.EncoderConfig.LevelKey = key
+	}
+}
+
+// WithTimeKey renames the "ts" key. An empty key leaves the timestamp out
+// entirely rather than writing a blank one.
+func WithTimeKey(key string) Option {
+	return func(o *options) {
+		o.config.EncoderConfig.TimeKey = key
+	}
+}
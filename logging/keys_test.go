@@ -0,0 +1,54 @@
+package logging
+
+import "testing"
+
+func TestKeyOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    []string
+		missing []string
+	}{
+		{
+			name:    "defaults",
+			want:    []string{"msg", "level", "ts"},
+			missing: []string{"log_message", "severity", "time"},
+		},
+		{
+			name:    "renamed",
+			opts:    []Option{WithMessageKey("log_message"), WithLevelKey("severity"), WithTimeKey("time")},
+			want:    []string{"log_message", "severity", "time"},
+			missing: []string{"msg", "level", "ts"},
+		},
+		{
+			name:    "omitted time",
+			opts:    []Option{WithTimeKey("")},
+			want:    []string{"msg", "level"},
+			missing: []string{"ts", ""},
+		},
+		{
+			name:    "omitted message and level",
+			opts:    []Option{WithMessageKey(""), WithLevelKey("")},
+			want:    []string{"ts"},
+			missing: []string{"msg", "level", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, tt.opts...)
+			logger.Info("fetched")
+
+			entry := entries()[0]
+			for _, key := range tt.want {
+				if _, ok := entry[key]; !ok {
+					t.Errorf("entry %v has no %q key", entry, key)
+				}
+			}
+			for _, key := range tt.missing {
+				if _, ok := entry[key]; ok {
+					t.Errorf("entry %v has a %q key", entry, key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Lazy bundles fields into a single field that is only encoded if the entry
+// is written. For fields whose cost is in the encoding, such as zap.Any on a
+// large struct, nothing is paid when the entry is below the logger's level.
+// The fields are inlined, so the output is the same as passing them directly.
+func Lazy(fields ...zap.Field) zap.Field {
+	return zap.Inline(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		return nil
+	}))
+}
+
+// LazyFunc goes one step further than Lazy and defers building the fields
+// too: fn is only called when the entry is encoded, so it can do expensive
+// work, like marshaling a request body, that should be skipped at levels that
+// are turned off:
+//
+//	logger.Debug("request", logging.LazyFunc(func() []zap.Field {
+//		return []zap.Field{zap.String("body", dump(req))} // not called at Info
+//	}))
+func LazyFunc(fn func() []zap.Field) zap.Field {
+	return zap.Inline(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, f := range fn() {
+			f.AddTo(enc)
+		}
+		return nil
+	}))
+}
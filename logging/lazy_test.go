@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLazyFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     zapcore.Level
+		wantCalls int
+	}{
+		{"enabled", zapcore.DebugLevel, 1},
+		{"dropped", zapcore.InfoLevel, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newFakeSyncer()
+			logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), ws, tt.level))
+			calls := 0
+
+			logger.Debug("request", LazyFunc(func() []zap.Field {
+				calls++
+				return []zap.Field{zap.String("body", "{}")}
+			}))
+
+			if calls != tt.wantCalls {
+				t.Errorf("fn called %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantCalls > 0 {
+				if got := decodeLine(t, ws.String())["body"]; got != "{}" {
+					t.Errorf("body = %v, want the field built by fn", got)
+				}
+			}
+		})
+	}
+}
+
+func TestLazyInlinesFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		field zap.Field
+	}{
+		{"Lazy", Lazy(zap.String("user", "frank"), zap.Int("attempt", 3))},
+		{"LazyFunc", LazyFunc(func() []zap.Field {
+			return []zap.Field{zap.String("user", "frank"), zap.Int("attempt", 3)}
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newFakeSyncer()
+			newSyncerLogger(ws).Info("fetched", tt.field)
+
+			entry := decodeLine(t, ws.String())
+			if entry["user"] != "frank" || entry["attempt"] != float64(3) {
+				t.Errorf("entry = %v, want user and attempt at the top level", entry)
+			}
+		})
+	}
+}
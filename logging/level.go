@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelAliases maps every accepted level name, lowercased, to its level.
+var levelAliases = map[string]zapcore.Level{
+	"trace":    zapcore.DebugLevel,
+	"debug":    zapcore.DebugLevel,
+	"info":     zapcore.InfoLevel,
+	"warn":     zapcore.WarnLevel,
+	"warning":  zapcore.WarnLevel,
+	"err":      zapcore.ErrorLevel,
+	"error":    zapcore.ErrorLevel,
+	"dpanic":   zapcore.DPanicLevel,
+	"panic":    zapcore.PanicLevel,
+	"crit":     zapcore.FatalLevel,
+	"critical": zapcore.FatalLevel,
+	"fatal":    zapcore.FatalLevel,
+}
+
+// validLevelNames lists levelAliases' keys for error messages.
+const validLevelNames = "trace, debug, info, warn, warning, err, error, dpanic, panic, crit, critical, fatal"
+
+// ParseLevel is a more forgiving zapcore.ParseLevel for levels written in
+// config files: it ignores case and surrounding spaces, and accepts the
+// aliases "trace" for debug, "warning" for warn, "err" for error, and "crit"
+// and "critical" for fatal.
+func ParseLevel(s string) (zapcore.Level, error) {
+	if level, ok := levelAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return level, nil
+	}
+	return zapcore.InfoLevel, fmt.Errorf("logging: unknown level %q, valid levels are %s", s, validLevelNames)
+}
+
+// WithAtomicLevel makes the logger use lvl as its minimum level. Keep a copy
+// of lvl around to change the level of a running logger, for example through
+// LevelHandler.
+func WithAtomicLevel(lvl zap.AtomicLevel) Option {
+	return func(o *options) {
+		o.config.Level = lvl
+	}
+}
+
+// LevelHandler serves lvl over HTTP. GET reports the current level as
+// {"level":"info"}, and PUT changes it using either the same JSON body or a
+// form-encoded level=debug. An unknown level is rejected with 400 and an
+// error message naming the bad value.
+//
+// The handler is zap's own AtomicLevel endpoint; changes take effect for the
+// very next log call.
+func LevelHandler(lvl zap.AtomicLevel) http.Handler {
+	return lvl
+}
+
+// lineLevel reads the level of an entry encoded as a JSON line by the
+// default encoder config, for sinks that map levels to severities of their
+// own. It defaults to InfoLevel.
+func lineLevel(p []byte) zapcore.Level {
+	var entry struct {
+		Level string `json:"level"`
+	}
+	if json.Unmarshal(p, &entry) != nil {
+		return zapcore.InfoLevel
+	}
+	var lvl zapcore.Level
+	if lvl.UnmarshalText([]byte(strings.ToLower(entry.Level))) != nil {
+		return zapcore.InfoLevel
+	}
+	return lvl
+}
@@ -0,0 +1,160 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevelHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+		wantLevel   zapcore.Level
+	}{
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"info"}`,
+			wantLevel:  zapcore.InfoLevel,
+		},
+		{
+			name:       "put json",
+			method:     http.MethodPut,
+			body:       `{"level":"debug"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"debug"}`,
+			wantLevel:  zapcore.DebugLevel,
+		},
+		{
+			name:        "put form",
+			method:      http.MethodPut,
+			contentType: "application/x-www-form-urlencoded",
+			body:        "level=warn",
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"level":"warn"}`,
+			wantLevel:   zapcore.WarnLevel,
+		},
+		{
+			name:       "put unknown level",
+			method:     http.MethodPut,
+			body:       `{"level":"loud"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "loud",
+			wantLevel:  zapcore.InfoLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lvl := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+			r := httptest.NewRequest(tt.method, "/log/level", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			LevelHandler(lvl).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", w.Body.String(), tt.wantBody)
+			}
+			if lvl.Level() != tt.wantLevel {
+				t.Errorf("level = %s, want %s", lvl.Level(), tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestLevelHandlerEnablesDebug(t *testing.T) {
+	lvl := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	logger, entries := newFileLogger(t, WithAtomicLevel(lvl))
+
+	logger.Debug("before")
+	w := httptest.NewRecorder()
+	LevelHandler(lvl).ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"debug"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d: %s", w.Code, w.Body)
+	}
+	logger.Debug("after")
+
+	got := entries()
+	if len(got) != 1 || got[0]["msg"] != "after" {
+		t.Errorf("got entries %v, want only the debug entry logged after the PUT", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want zapcore.Level
+	}{
+		{"trace", zapcore.DebugLevel},
+		{"debug", zapcore.DebugLevel},
+		{"info", zapcore.InfoLevel},
+		{"warn", zapcore.WarnLevel},
+		{"warning", zapcore.WarnLevel},
+		{"WARNING", zapcore.WarnLevel},
+		{" warn ", zapcore.WarnLevel},
+		{"err", zapcore.ErrorLevel},
+		{"error", zapcore.ErrorLevel},
+		{"dpanic", zapcore.DPanicLevel},
+		{"panic", zapcore.PanicLevel},
+		{"crit", zapcore.FatalLevel},
+		{"critical", zapcore.FatalLevel},
+		{"Fatal", zapcore.FatalLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if err != nil {
+				t.Fatalf("ParseLevel(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLevelInvalid(t *testing.T) {
+	for _, in := range []string{"", "loud", "warnings"} {
+		_, err := ParseLevel(in)
+		if err == nil {
+			t.Errorf("ParseLevel(%q) succeeded", in)
+			continue
+		}
+		if !strings.Contains(err.Error(), validLevelNames) {
+			t.Errorf("ParseLevel(%q) error %q does not list the valid levels", in, err)
+		}
+	}
+}
+
+func TestLineLevel(t *testing.T) {
+	tests := []struct {
+		line string
+		want zapcore.Level
+	}{
+		{`{"level":"debug","msg":"x"}`, zapcore.DebugLevel},
+		{`{"level":"WARN","msg":"x"}`, zapcore.WarnLevel},
+		{`{"level":"error"}`, zapcore.ErrorLevel},
+		{`{"level":"loud"}`, zapcore.InfoLevel},
+		{`{"msg":"no level"}`, zapcore.InfoLevel},
+		{`not json`, zapcore.InfoLevel},
+	}
+	for _, tt := range tests {
+		if got := lineLevel([]byte(tt.line)); got != tt.want {
+			t.Errorf("lineLevel(%s) = %s, want %s", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewLeveledFileLogger builds a JSON logger that keeps one file per level in
+// dir: info.log, warn.log and error.log. Each entry lands only in the file
+// for its own level; Debug entries are not written, and DPanic, Panic and
+// Fatal entries go to error.log.
+//
+// dir is created if needed and existing files are appended to.
+func NewLeveledFileLogger(dir string) (*zap.Logger, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("logging: create log directory: %w", err)
+	}
+
+	files := []struct {
+		name    string
+		enabled zap.LevelEnablerFunc
+	}{
+		{"info.log", func(l zapcore.Level) bool { return l == zapcore.InfoLevel }},
+		{"warn.log", func(l zapcore.Level) bool { return l == zapcore.WarnLevel }},
+		{"error.log", func(l zapcore.Level) bool { return l >= zapcore.ErrorLevel }},
+	}
+
+	enc := zapcore.NewJSONEncoder(newOptions().config.EncoderConfig)
+	cores := make([]zapcore.Core, 0, len(files))
+	opened := make([]*os.File, 0, len(files))
+	for _, file := range files {
+		f, err := os.OpenFile(filepath.Join(dir, file.name), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			for _, f := range opened {
+				f.Close()
+			}
+			return nil, fmt.Errorf("logging: open log file: %w", err)
+		}
+		opened = append(opened, f)
+		cores = append(cores, zapcore.NewCore(enc.Clone(), zapcore.Lock(f), file.enabled))
+	}
+	return zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), nil
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levelsIn returns the levels of the entries in the file at path, in order.
+func levelsIn(t *testing.T, path string) []string {
+	t.Helper()
+	var levels []string
+	for _, e := range readEntries(t, path) {
+		levels = append(levels, e["level"].(string))
+	}
+	return levels
+}
+
+func TestNewLeveledFileLogger(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs", "app")
+	logger, err := NewLeveledFileLogger(dir)
+	if err != nil {
+		t.Fatalf("NewLeveledFileLogger: %v", err)
+	}
+	for _, level := range []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel, zapcore.DPanicLevel} {
+		logger.Log(level, level.String())
+	}
+	_ = logger.Sync()
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"info.log", []string{"info"}},
+		{"warn.log", []string{"warn"}},
+		{"error.log", []string{"error", "dpanic"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := levelsIn(t, filepath.Join(dir, tt.file)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("levels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLeveledFileLoggerAppends(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "error.log")
+	if err := os.WriteFile(path, []byte(`{"level":"error","msg":"earlier"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logger, err := NewLeveledFileLogger(dir)
+	if err != nil {
+		t.Fatalf("NewLeveledFileLogger: %v", err)
+	}
+	logger.Error("later")
+	_ = logger.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 || entries[0]["msg"] != "earlier" || entries[1]["msg"] != "later" {
+		t.Errorf("got entries %v, want the earlier entry kept before the new one", entries)
+	}
+}
+
+func TestNewLeveledFileLoggerUnwritableDirectory(t *testing.T) {
+	parent := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(parent, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLeveledFileLogger(filepath.Join(parent, "logs")); err == nil {
+		t.Error("NewLeveledFileLogger succeeded with a directory below a regular file")
+	}
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// noLineEndingPrefix names the encodings WithLineEnding("") swaps the
+// configured one for: the encoding after the prefix, minus the line ending.
+const noLineEndingPrefix = "logging-no-line-ending-"
+
+func init() {
+	for _, encoding := range []string{"json", "console", "logfmt", prettyJSONEncoding} {
+		name := noLineEndingPrefix + encoding
+		_ = zap.RegisterEncoder(name, func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+			return newEncoder(name, cfg)
+		})
+	}
+}
+
+// WithLineEnding sets what ends each entry: "\n", the default, "\r\n" for
+// shippers that expect Windows line endings, or "" for none at all, so that
+// consecutive entries follow each other directly. The entries themselves,
+// JSON documents with the json encoding, are the same whatever the ending.
+//
+// Build fails for any other ending.
+func WithLineEnding(ending string) Option {
+	return func(o *options) {
+		switch ending {
+		case "\n", "\r\n":
+			o.config.EncoderConfig.LineEnding = ending
+			o.noLineEnding = false
+		case "":
+			// zap's encoders read an empty LineEnding as the default one,
+			// so it is trimmed after encoding instead.
+			o.config.EncoderConfig.LineEnding = zapcore.DefaultLineEnding
+			o.noLineEnding = true
+		default:
+			o.setErr(fmt.Errorf("logging: unsupported line ending %q, valid endings are \"\\n\", \"\\r\\n\" and \"\"", ending))
+		}
+	}
+}
+
+// newNoLineEndingEncoder returns the encoding named by a noLineEndingPrefix
+// encoding.
+func newNoLineEndingEncoder(encoding string, cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	cfg.LineEnding = zapcore.DefaultLineEnding
+	enc, err := newEncoder(strings.TrimPrefix(encoding, noLineEndingPrefix), cfg)
+	if err != nil {
+		return nil, err
+	}
+	return noLineEndingEncoder{enc}, nil
+}
+
+// noLineEndingEncoder removes the trailing newline of the encoder it wraps.
+type noLineEndingEncoder struct {
+	zapcore.Encoder
+}
+
+func (e noLineEndingEncoder) Clone() zapcore.Encoder {
+	return noLineEndingEncoder{e.Encoder.Clone()}
+}
+
+func (e noLineEndingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	line.TrimNewline()
+	return line, nil
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithLineEnding(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		ending   string
+	}{
+		{"default", "json", "\n"},
+		{"CRLF", "json", "\r\n"},
+		{"none", "json", ""},
+		{"console, none", "console", ""},
+		{"console, CRLF", "console", "\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.log")
+			logger, err := NewLogger(WithOutputPaths(path), WithEncoding(tt.encoding), WithLineEnding(tt.ending))
+			if err != nil {
+				t.Fatalf("NewLogger: %v", err)
+			}
+			logger.Info("first")
+			logger.Info("second")
+			logger.Sync()
+
+			out, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tail := strings.TrimSuffix(string(out), tt.ending)
+			switch {
+			case !strings.HasSuffix(string(out), tt.ending):
+				t.Errorf("output %q does not end in %q", out, tt.ending)
+			case strings.HasSuffix(tail, "\n") || strings.HasSuffix(tail, "\r"):
+				t.Errorf("output %q ends in more than %q", out, tt.ending)
+			}
+			if tt.encoding != "json" {
+				return
+			}
+			dec := json.NewDecoder(bytes.NewReader(out))
+			var msgs []interface{}
+			for {
+				var entry map[string]interface{}
+				if err := dec.Decode(&entry); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("output %q is not a stream of JSON entries: %v", out, err)
+				}
+				msgs = append(msgs, entry["msg"])
+			}
+			if len(msgs) != 2 || msgs[0] != "first" || msgs[1] != "second" {
+				t.Errorf("decoded messages %v, want [first second]", msgs)
+			}
+		})
+	}
+}
+
+func TestWithLineEndingInvalid(t *testing.T) {
+	if _, err := NewLogger(WithLineEnding("\r")); err == nil {
+		t.Error("NewLogger succeeded with a lone carriage return")
+	}
+}
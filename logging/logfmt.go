@@ -0,0 +1,354 @@
+package logging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+func init() {
+	// Makes "logfmt" usable as zap.Config.Encoding as well.
+	_ = zap.RegisterEncoder("logfmt", func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return NewLogfmtEncoder(cfg), nil
+	})
+}
+
+// NewLogfmtEncoder returns an encoder that renders each entry as a line of
+// space-separated key=value pairs, e.g.
+//
+//	level=info ts=2023-10-24T11:06:18+08:00 msg="failed to fetch URL" attempt=3
+//
+// Values containing spaces, quotes, equals signs or control characters are
+// double-quoted with Go escaping. Fields of nested objects are flattened into
+// dotted keys (request.url=...) and arrays are rendered as [a,b,c].
+func NewLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{EncoderConfig: &cfg, buf: logfmtPool.Get()}
+}
+
+type logfmtEncoder struct {
+	*zapcore.EncoderConfig
+	buf *buffer.Buffer
+	// namespaces prefix every key added after OpenNamespace or while an
+	// object is being marshaled.
+	namespaces []string
+}
+
+func (enc *logfmtEncoder) Clone() zapcore.Encoder {
+	return enc.clone()
+}
+
+func (enc *logfmtEncoder) clone() *logfmtEncoder {
+	clone := &logfmtEncoder{
+		EncoderConfig: enc.EncoderConfig,
+		buf:           logfmtPool.Get(),
+		namespaces:    append([]string(nil), enc.namespaces...),
+	}
+	clone.buf.Write(enc.buf.Bytes())
+	return clone
+}
+
+func (enc *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := &logfmtEncoder{EncoderConfig: enc.EncoderConfig, buf: logfmtPool.Get()}
+
+	if final.LevelKey != "" && final.EncodeLevel != nil {
+		final.addElements(final.LevelKey, ent.Level.String(), func(ae zapcore.PrimitiveArrayEncoder) {
+			final.EncodeLevel(ent.Level, ae)
+		})
+	}
+	if final.TimeKey != "" {
+		final.AddTime(final.TimeKey, ent.Time)
+	}
+	if ent.LoggerName != "" && final.NameKey != "" {
+		nameEncoder := final.EncodeName
+		if nameEncoder == nil {
+			nameEncoder = zapcore.FullNameEncoder
+		}
+		final.addElements(final.NameKey, ent.LoggerName, func(ae zapcore.PrimitiveArrayEncoder) {
+			nameEncoder(ent.LoggerName, ae)
+		})
+	}
+	if ent.Caller.Defined {
+		if final.CallerKey != "" && final.EncodeCaller != nil {
+			final.addElements(final.CallerKey, ent.Caller.String(), func(ae zapcore.PrimitiveArrayEncoder) {
+				final.EncodeCaller(ent.Caller, ae)
+			})
+		}
+		if final.FunctionKey != "" {
+			final.AddString(final.FunctionKey, ent.Caller.Function)
+		}
+	}
+	if final.MessageKey != "" {
+		final.AddString(final.MessageKey, ent.Message)
+	}
+	if enc.buf.Len() > 0 {
+		final.addSeparator()
+		final.buf.Write(enc.buf.Bytes())
+	}
+
+	final.namespaces = append(final.namespaces, enc.namespaces...)
+	for _, f := range fields {
+		f.AddTo(final)
+	}
+	final.namespaces = nil
+	if ent.Stack != "" && final.StacktraceKey != "" {
+		final.AddString(final.StacktraceKey, ent.Stack)
+	}
+
+	lineEnding := final.LineEnding
+	if lineEnding == "" {
+		lineEnding = zapcore.DefaultLineEnding
+	}
+	final.buf.AppendString(lineEnding)
+	return final.buf, nil
+}
+
+func (enc *logfmtEncoder) addSeparator() {
+	if enc.buf.Len() > 0 {
+		enc.buf.AppendByte(' ')
+	}
+}
+
+func (enc *logfmtEncoder) addKey(key string) {
+	enc.addSeparator()
+	for _, ns := range enc.namespaces {
+		enc.buf.AppendString(logfmtKey(ns))
+		enc.buf.AppendByte('.')
+	}
+	enc.buf.AppendString(logfmtKey(key))
+	enc.buf.AppendByte('=')
+}
+
+// addValue writes an already formatted value, quoting it if needed.
+func (enc *logfmtEncoder) addValue(value string) {
+	if logfmtNeedsQuote(value) {
+		enc.buf.AppendString(strconv.Quote(value))
+		return
+	}
+	enc.buf.AppendString(value)
+}
+
+// addElements runs one of zap's element encoders (EncodeTime, EncodeLevel,
+// ...) and writes what it appended as the value of key, or fallback if it
+// appended nothing.
+func (enc *logfmtEncoder) addElements(key, fallback string, encode func(zapcore.PrimitiveArrayEncoder)) {
+	values := &logfmtValues{cfg: enc.EncoderConfig}
+	encode(values)
+	enc.addKey(key)
+	if len(values.elems) == 0 {
+		enc.addValue(fallback)
+		return
+	}
+	enc.addValue(strings.Join(values.elems, ","))
+}
+
+func (enc *logfmtEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	values := &logfmtValues{cfg: enc.EncoderConfig}
+	err := arr.MarshalLogArray(values)
+	enc.addKey(key)
+	enc.addValue("[" + strings.Join(values.elems, ",") + "]")
+	return err
+}
+
+func (enc *logfmtEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	enc.namespaces = append(enc.namespaces, key)
+	err := obj.MarshalLogObject(enc)
+	enc.namespaces = enc.namespaces[:len(enc.namespaces)-1]
+	return err
+}
+
+func (enc *logfmtEncoder) AddBinary(key string, value []byte) {
+	enc.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (enc *logfmtEncoder) AddByteString(key string, value []byte) {
+	enc.AddString(key, string(value))
+}
+
+func (enc *logfmtEncoder) AddBool(key string, value bool) {
+	enc.addKey(key)
+	enc.buf.AppendBool(value)
+}
+
+func (enc *logfmtEncoder) AddComplex128(key string, value complex128) {
+	enc.addKey(key)
+	enc.buf.AppendString(strconv.FormatComplex(value, 'f', -1, 128))
+}
+
+func (enc *logfmtEncoder) AddComplex64(key string, value complex64) {
+	enc.addKey(key)
+	enc.buf.AppendString(strconv.FormatComplex(complex128(value), 'f', -1, 64))
+}
+
+func (enc *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	if enc.EncodeDuration == nil {
+		enc.AddInt64(key, int64(value))
+		return
+	}
+	enc.addElements(key, strconv.FormatInt(int64(value), 10), func(ae zapcore.PrimitiveArrayEncoder) {
+		enc.EncodeDuration(value, ae)
+	})
+}
+
+func (enc *logfmtEncoder) AddFloat64(key string, value float64) {
+	enc.addKey(key)
+	enc.buf.AppendFloat(value, 64)
+}
+
+func (enc *logfmtEncoder) AddFloat32(key string, value float32) {
+	enc.addKey(key)
+	enc.buf.AppendFloat(float64(value), 32)
+}
+
+func (enc *logfmtEncoder) AddInt(key string, value int)     { enc.AddInt64(key, int64(value)) }
+func (enc *logfmtEncoder) AddInt32(key string, value int32) { enc.AddInt64(key, int64(value)) }
+func (enc *logfmtEncoder) AddInt16(key string, value int16) { enc.AddInt64(key, int64(value)) }
+func (enc *logfmtEncoder) AddInt8(key string, value int8)   { enc.AddInt64(key, int64(value)) }
+
+func (enc *logfmtEncoder) AddInt64(key string, value int64) {
+	enc.addKey(key)
+	enc.buf.AppendInt(value)
+}
+
+func (enc *logfmtEncoder) AddString(key, value string) {
+	enc.addKey(key)
+	enc.addValue(value)
+}
+
+func (enc *logfmtEncoder) AddTime(key string, value time.Time) {
+	if enc.EncodeTime == nil {
+		enc.AddInt64(key, value.UnixNano())
+		return
+	}
+	enc.addElements(key, strconv.FormatInt(value.UnixNano(), 10), func(ae zapcore.PrimitiveArrayEncoder) {
+		enc.EncodeTime(value, ae)
+	})
+}
+
+func (enc *logfmtEncoder) AddUint(key string, value uint)       { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUint32(key string, value uint32)   { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUint16(key string, value uint16)   { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUint8(key string, value uint8)     { enc.AddUint64(key, uint64(value)) }
+func (enc *logfmtEncoder) AddUintptr(key string, value uintptr) { enc.AddUint64(key, uint64(value)) }
+
+func (enc *logfmtEncoder) AddUint64(key string, value uint64) {
+	enc.addKey(key)
+	enc.buf.AppendUint(value)
+}
+
+func (enc *logfmtEncoder) AddReflected(key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	enc.AddString(key, string(b))
+	return nil
+}
+
+func (enc *logfmtEncoder) OpenNamespace(key string) {
+	enc.namespaces = append(enc.namespaces, key)
+}
+
+// logfmtValues collects the values appended by element encoders and array
+// marshalers as strings, so they can be joined into a single logfmt value.
+type logfmtValues struct {
+	cfg   *zapcore.EncoderConfig
+	elems []string
+}
+
+func (v *logfmtValues) add(s string) { v.elems = append(v.elems, s) }
+
+func (v *logfmtValues) AppendBool(b bool)             { v.add(strconv.FormatBool(b)) }
+func (v *logfmtValues) AppendByteString(b []byte)     { v.add(string(b)) }
+func (v *logfmtValues) AppendComplex128(c complex128) { v.add(strconv.FormatComplex(c, 'f', -1, 128)) }
+func (v *logfmtValues) AppendComplex64(c complex64) {
+	v.add(strconv.FormatComplex(complex128(c), 'f', -1, 64))
+}
+func (v *logfmtValues) AppendFloat64(f float64) { v.add(strconv.FormatFloat(f, 'f', -1, 64)) }
+func (v *logfmtValues) AppendFloat32(f float32) { v.add(strconv.FormatFloat(float64(f), 'f', -1, 32)) }
+func (v *logfmtValues) AppendInt(i int)         { v.AppendInt64(int64(i)) }
+func (v *logfmtValues) AppendInt64(i int64)     { v.add(strconv.FormatInt(i, 10)) }
+func (v *logfmtValues) AppendInt32(i int32)     { v.AppendInt64(int64(i)) }
+func (v *logfmtValues) AppendInt16(i int16)     { v.AppendInt64(int64(i)) }
+func (v *logfmtValues) AppendInt8(i int8)       { v.AppendInt64(int64(i)) }
+func (v *logfmtValues) AppendString(s string)   { v.add(s) }
+func (v *logfmtValues) AppendUint(u uint)       { v.AppendUint64(uint64(u)) }
+func (v *logfmtValues) AppendUint64(u uint64)   { v.add(strconv.FormatUint(u, 10)) }
+func (v *logfmtValues) AppendUint32(u uint32)   { v.AppendUint64(uint64(u)) }
+func (v *logfmtValues) AppendUint16(u uint16)   { v.AppendUint64(uint64(u)) }
+func (v *logfmtValues) AppendUint8(u uint8)     { v.AppendUint64(uint64(u)) }
+func (v *logfmtValues) AppendUintptr(u uintptr) { v.AppendUint64(uint64(u)) }
+
+func (v *logfmtValues) AppendDuration(d time.Duration) {
+	n := len(v.elems)
+	if v.cfg.EncodeDuration != nil {
+		v.cfg.EncodeDuration(d, v)
+	}
+	if len(v.elems) == n {
+		v.AppendInt64(int64(d))
+	}
+}
+
+func (v *logfmtValues) AppendTime(t time.Time) {
+	n := len(v.elems)
+	if v.cfg.EncodeTime != nil {
+		v.cfg.EncodeTime(t, v)
+	}
+	if len(v.elems) == n {
+		v.AppendInt64(t.UnixNano())
+	}
+}
+
+func (v *logfmtValues) AppendArray(arr zapcore.ArrayMarshaler) error {
+	nested := &logfmtValues{cfg: v.cfg}
+	err := arr.MarshalLogArray(nested)
+	v.add("[" + strings.Join(nested.elems, ",") + "]")
+	return err
+}
+
+func (v *logfmtValues) AppendObject(obj zapcore.ObjectMarshaler) error {
+	enc := &logfmtEncoder{EncoderConfig: v.cfg, buf: logfmtPool.Get()}
+	defer enc.buf.Free()
+	err := obj.MarshalLogObject(enc)
+	v.add("{" + enc.buf.String() + "}")
+	return err
+}
+
+func (v *logfmtValues) AppendReflected(value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	v.add(string(b))
+	return nil
+}
+
+// logfmtNeedsQuote reports whether s has to be quoted to be read back as a
+// single logfmt value.
+func logfmtNeedsQuote(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// logfmtKey replaces the characters that would make key ambiguous.
+func logfmtKey(key string) string {
+	if !strings.ContainsAny(key, " =\"") {
+		return key
+	}
+	return strings.NewReplacer(" ", "_", "=", "_", "\"", "_").Replace(key)
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// testEntryTime is the time of the entries encoded directly by the tests.
+var testEntryTime = time.Date(2023, 10, 24, 11, 6, 18, 0, time.UTC)
+
+// encodeEntry encodes an Info entry with msg and fields using enc.
+func encodeEntry(t *testing.T, enc zapcore.Encoder, msg string, fields ...zap.Field) string {
+	t.Helper()
+	buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Time: testEntryTime, Message: msg}, fields)
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+	defer buf.Free()
+	return buf.String()
+}
+
+type logfmtRequest struct{}
+
+func (logfmtRequest) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("url", "http://marmotedu.com")
+	return enc.AddArray("ids", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+		ae.AppendInt(1)
+		ae.AppendInt(2)
+		return nil
+	}))
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []zap.Field
+		want   string
+	}{
+		{"no fields", nil, ""},
+		{"plain string", []zap.Field{zap.String("user", "marmotedu")}, " user=marmotedu"},
+		{"space", []zap.Field{zap.String("reason", "timed out")}, ` reason="timed out"`},
+		{"quote", []zap.Field{zap.String("q", `say "hi"`)}, ` q="say \"hi\""`},
+		{"equals sign", []zap.Field{zap.String("query", "a=b")}, ` query="a=b"`},
+		{"newline", []zap.Field{zap.String("text", "a\nb")}, ` text="a\nb"`},
+		{"empty", []zap.Field{zap.String("empty", "")}, ` empty=""`},
+		{"key with space", []zap.Field{zap.String("user name", "a")}, " user_name=a"},
+		{"numbers", []zap.Field{zap.Int("attempt", 3), zap.Float64("ratio", 0.5), zap.Bool("ok", true)}, " attempt=3 ratio=0.5 ok=true"},
+		{"duration", []zap.Field{zap.Duration("backoff", time.Second)}, " backoff=1"},
+		{"object", []zap.Field{zap.Object("request", logfmtRequest{})}, " request.url=http://marmotedu.com request.ids=[1,2]"},
+		{"namespace", []zap.Field{zap.Namespace("db"), zap.String("table", "users")}, " db.table=users"},
+		{"reflected", []zap.Field{zap.Any("tags", map[string]int{"a": 1})}, ` tags="{\"a\":1}"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := NewLogfmtEncoder(zap.NewProductionEncoderConfig())
+			got := encodeEntry(t, enc, "failed to fetch URL", tt.fields...)
+			want := `level=info ts=1698145578 msg="failed to fetch URL"` + tt.want + "\n"
+			if got != want {
+				t.Errorf("got  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLogfmtEncoderClone(t *testing.T) {
+	enc := NewLogfmtEncoder(zap.NewProductionEncoderConfig())
+	enc.AddString("service", "demo")
+	clone := enc.Clone()
+	clone.AddInt("attempt", 3)
+
+	tests := []struct {
+		name string
+		enc  zapcore.Encoder
+		want string
+	}{
+		{"original", enc, "level=info ts=1698145578 msg=hi service=demo\n"},
+		{"clone", clone, "level=info ts=1698145578 msg=hi service=demo attempt=3\n"},
+	}
+	for _, tt := range tests {
+		if got := encodeEntry(t, tt.enc, "hi"); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package logging holds the zap setup used by the demo so that it can be
+// reused from tests and other programs instead of living inside main.
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option configures the logger built by NewLogger.
+type Option func(*options)
+
+type options struct {
+	config zap.Config
+	// sinks, when non-empty, replace config.OutputPaths as the destination
+	// of encoded entries. They cover writers zap cannot open from a path,
+	// such as a rotating file.
+	sinks []zapcore.WriteSyncer
+	// rotatingFiles are the sinks added by WithRotatingFile, which
+	// rotationCodec applies to.
+	rotatingFiles []*rotatingFile
+	rotationCodec string
+	// buffer, when set, buffers the writes to the sinks or output paths.
+	buffer *bufferConfig
+	// wrappers decorate the core in the order the options were given. They
+	// sit beneath the sampler, so they can rely on Enabled alone in Check.
+	wrappers []func(zapcore.Core) zapcore.Core
+	// zapOptions are applied to the built logger after everything else.
+	zapOptions []zap.Option
+	// fatalHooks run, last registered first, before a Fatal entry exits.
+	fatalHooks []func()
+	// fatalExitCode is the status a Fatal entry exits with.
+	fatalExitCode int
+	// utc converts entry times to UTC before EncodeTime formats them.
+	utc bool
+	// prettyJSON indents the output of the json encoding.
+	prettyJSON bool
+	// noLineEnding trims the line ending off every entry.
+	noLineEnding bool
+	// flattenSep, when set, joins the keys of nested fields into top-level
+	// ones.
+	flattenSep string
+	// samplingExcept, when set, exempts entries at that level and above
+	// from config.Sampling.
+	samplingExcept *zapcore.Level
+	// shortCaller and functionCaller select the caller encoder.
+	shortCaller, functionCaller bool
+	// sanitizers are the Sanitizers given to WithSanitizer, applied in
+	// order by a single core.
+	sanitizers []Sanitizer
+	// err records the first failure of an option so that NewLogger can
+	// report it instead of building a half-configured logger.
+	err error
+}
+
+// newOptions returns the defaults the demo has always used: zap's production
+// config with RFC3339 timestamps instead of epoch floats.
+func newOptions() *options {
+	config := zap.NewProductionConfig()
+	config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(time.RFC3339)
+	return &options{config: config, fatalExitCode: 1}
+}
+
+func (o *options) setErr(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// NewLogger builds a JSON logger that writes InfoLevel and above to standard
+// error with RFC3339 timestamps, adjusted by opts. It is the same as Build.
+//
+// Calling Sync or Close before the program exits is still the caller's
+// responsibility.
+func NewLogger(opts ...Option) (*zap.Logger, error) {
+	return Build(opts...)
+}
+
+// Build applies opts on top of the defaults described at NewLogger and
+// assembles the resulting configuration into a logger.
+//
+// Calling Sync or Close before the program exits is the caller's
+// responsibility.
+func Build(opts ...Option) (*zap.Logger, error) {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o.build()
+}
+
+// WithLevel sets the minimum level of entries the logger writes.
+func WithLevel(level zapcore.Level) Option {
+	return func(o *options) {
+		o.config.Level = zap.NewAtomicLevelAt(level)
+	}
+}
+
+// WithEncoding selects how entries are rendered: "json" (the default),
+// "console" or "logfmt". Build fails for any other value.
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		if _, err := newEncoder(encoding, o.config.EncoderConfig); err != nil {
+			o.setErr(err)
+			return
+		}
+		o.config.Encoding = encoding
+	}
+}
+
+// WithOutputPaths replaces standard error with the given outputs, which are
+// anything zap.Open accepts: "stdout", "stderr" or file paths.
+func WithOutputPaths(paths ...string) Option {
+	return func(o *options) {
+		o.config.OutputPaths = paths
+	}
+}
+
+// WithTimeLayout formats timestamps with the given time.Format layout. An
+// empty layout means time.RFC3339, the default. It replaces WithEpochNanos
+// when given after it, and the other way around.
+func WithTimeLayout(layout string) Option {
+	return func(o *options) {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		o.config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(layout)
+	}
+}
+
+// WithEpochNanos writes timestamps as integer nanoseconds since the Unix
+// epoch, for backends that ingest them as is. Of WithEpochNanos and
+// WithTimeLayout, the one given last wins.
+func WithEpochNanos() Option {
+	return func(o *options) {
+		o.config.EncoderConfig.EncodeTime = zapcore.EpochNanosTimeEncoder
+	}
+}
+
+func (o *options) build() (*zap.Logger, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	// Let zap.Config build everything it knows about (caller, stacktrace,
+	// initial fields, error output) and layer our own cores on top. Sampling
+	// is taken out of the config and re-applied last, so that it stays the
+	// outermost core like it is in a plain zap.Config.Build.
+	cfg := o.config
+	sampling := cfg.Sampling
+	cfg.Sampling = nil
+	if o.utc && cfg.EncoderConfig.EncodeTime != nil {
+		cfg.EncoderConfig.EncodeTime = utcTimeEncoder(cfg.EncoderConfig.EncodeTime)
+	}
+	if o.shortCaller || o.functionCaller {
+		cfg.EncoderConfig.EncodeCaller = callerEncoder(o.shortCaller, o.functionCaller)
+	}
+	if o.prettyJSON && cfg.Encoding == "json" {
+		cfg.Encoding = prettyJSONEncoding
+	}
+	if o.noLineEnding {
+		cfg.Encoding = noLineEndingPrefix + cfg.Encoding
+	}
+
+	if o.rotationCodec != "" {
+		for _, f := range o.rotatingFiles {
+			f.setCodec(o.rotationCodec)
+		}
+	}
+
+	var zapOptions []zap.Option
+	b := &built{config: o.effectiveConfig(cfg), level: cfg.Level}
+	sinks := o.sinks
+	if (o.buffer != nil || o.flattenSep != "") && len(sinks) == 0 {
+		// Open the output paths here instead of in cfg.Build, so that the
+		// buffer or the flattening encoder can go in front of them.
+		ws, closeOutputs, err := zap.Open(cfg.OutputPaths...)
+		if err != nil {
+			return nil, err
+		}
+		b.closers = append(b.closers, func() error {
+			closeOutputs()
+			return nil
+		})
+		sinks = []zapcore.WriteSyncer{ws}
+	}
+	if len(sinks) > 0 {
+		enc, err := newEncoder(cfg.Encoding, cfg.EncoderConfig)
+		if err != nil {
+			return nil, err
+		}
+		if o.flattenSep != "" {
+			enc = newFlattenEncoder(enc, cfg.EncoderConfig, o.flattenSep)
+		}
+		cfg.OutputPaths = nil
+		sink := zapcore.NewMultiWriteSyncer(sinks...)
+		if o.buffer != nil {
+			buffered := &zapcore.BufferedWriteSyncer{WS: sink, Size: o.buffer.size, FlushInterval: o.buffer.flushInterval}
+			b.closers = append(b.closers, buffered.Stop)
+			sink = buffered
+		}
+		zapOptions = append(zapOptions, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return zapcore.NewCore(enc, sink, cfg.Level)
+		}))
+	}
+	for _, wrap := range o.wrappers {
+		zapOptions = append(zapOptions, zap.WrapCore(wrap))
+	}
+	if sampling != nil {
+		zapOptions = append(zapOptions, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			var samplerOpts []zapcore.SamplerOption
+			if sampling.Hook != nil {
+				samplerOpts = append(samplerOpts, zapcore.SamplerHook(sampling.Hook))
+			}
+			sampled := zapcore.NewSamplerWithOptions(core, time.Second, sampling.Initial, sampling.Thereafter, samplerOpts...)
+			if o.samplingExcept != nil {
+				return &unsampledCore{sampled: sampled, raw: core, min: *o.samplingExcept}
+			}
+			return sampled
+		}))
+	}
+	if len(o.fatalHooks) > 0 || o.fatalExitCode != 1 {
+		zapOptions = append(zapOptions, zap.WithFatalHook(o.fatalHook()))
+	}
+	zapOptions = append(zapOptions, o.zapOptions...)
+	zapOptions = append(zapOptions, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &builtCore{Core: core, built: b}
+	}))
+	logger, err := cfg.Build(zapOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return logger, nil
+}
+
+func newEncoder(encoding string, cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	switch encoding {
+	case "json":
+		return zapcore.NewJSONEncoder(cfg), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(cfg), nil
+	case "logfmt":
+		return NewLogfmtEncoder(cfg), nil
+	case prettyJSONEncoding:
+		return newPrettyJSONEncoder(cfg), nil
+	default:
+		if strings.HasPrefix(encoding, noLineEndingPrefix) {
+			return newNoLineEndingEncoder(encoding, cfg)
+		}
+		return nil, fmt.Errorf("logging: unknown encoding %q", encoding)
+	}
+}
@@ -0,0 +1,336 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newFileLogger builds a logger with opts that writes to a file in a
+// temporary directory, and returns it with a function decoding the JSON
+// entries written so far.
+func newFileLogger(t *testing.T, opts ...Option) (*zap.Logger, func() []map[string]interface{}) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.log")
+	logger, err := NewLogger(append([]Option{WithOutputPaths(path)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	return logger, func() []map[string]interface{} {
+		t.Helper()
+		_ = logger.Sync()
+		return readEntries(t, path)
+	}
+}
+
+// readEntries decodes the JSON entries in the file at path, one per line.
+func readEntries(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []map[string]interface{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		entries = append(entries, decodeLine(t, s.Text()))
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestNewLoggerDefaults(t *testing.T) {
+	logger, entries := newFileLogger(t)
+	logger.Debug("dropped")
+	logger.Info("failed to fetch URL", zap.String("url", "http://marmotedu.com"), zap.Int("attempt", 3))
+
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1: %v", len(got), got)
+	}
+	entry := got[0]
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{"level", "info"},
+		{"msg", "failed to fetch URL"},
+		{"url", "http://marmotedu.com"},
+		{"attempt", float64(3)},
+	}
+	for _, tt := range tests {
+		if entry[tt.key] != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, entry[tt.key], tt.want)
+		}
+	}
+	ts, ok := entry["ts"].(string)
+	if !ok {
+		t.Fatalf("ts = %v, want an RFC3339 string", entry["ts"])
+	}
+	if _, err := time.Parse(time.RFC3339, ts); err != nil {
+		t.Errorf("ts %q is not RFC3339: %v", ts, err)
+	}
+	if _, ok := entry["caller"]; !ok {
+		t.Error("entry has no caller")
+	}
+}
+
+func TestNewLoggerOptionError(t *testing.T) {
+	logger, err := NewLogger(WithEncoding("xml"))
+	if err == nil {
+		t.Fatal("NewLogger succeeded with an unknown encoding")
+	}
+	if logger != nil {
+		t.Error("NewLogger returned a logger along with its error")
+	}
+}
+
+// redirectStderr points os.Stderr at a temporary file until the test ends,
+// for loggers that write to it, and returns a function reading what was
+// written so far.
+func redirectStderr(t *testing.T) func() string {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = f
+	t.Cleanup(func() {
+		os.Stderr = stderr
+		f.Close()
+	})
+	return func() string {
+		t.Helper()
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+}
+
+// fakeSyncer is a WriteSyncer that keeps what is written to it and counts
+// calls to Sync, which returns err after waiting for delay.
+type fakeSyncer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	syncs  int
+	err    error
+	delay  time.Duration
+	synced chan struct{}
+}
+
+func newFakeSyncer() *fakeSyncer {
+	return &fakeSyncer{synced: make(chan struct{}, 100)}
+}
+
+func (s *fakeSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *fakeSyncer) Sync() error {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	s.syncs++
+	s.mu.Unlock()
+	// Signal without blocking, so that a syncer synced more often than
+	// anyone waits on synced, as by a ticker, does not stall.
+	select {
+	case s.synced <- struct{}{}:
+	default:
+	}
+	return s.err
+}
+
+func (s *fakeSyncer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+func (s *fakeSyncer) syncCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncs
+}
+
+// newSyncerLogger returns a JSON logger writing to ws at DebugLevel.
+func newSyncerLogger(ws zapcore.WriteSyncer) *zap.Logger {
+	return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), ws, zapcore.DebugLevel))
+}
+
+func TestBuildOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// check inspects the single line logged by logEntries.
+		check func(t *testing.T, line string)
+	}{
+		{
+			name: "level",
+			opts: []Option{WithLevel(zapcore.DebugLevel)},
+			check: func(t *testing.T, line string) {
+				if !strings.Contains(line, `"msg":"debug"`) {
+					t.Errorf("debug entry missing: %s", line)
+				}
+			},
+		},
+		{
+			name: "console encoding",
+			opts: []Option{WithEncoding("console")},
+			check: func(t *testing.T, line string) {
+				if strings.HasPrefix(line, "{") || !strings.Contains(line, "\tinfo\t") {
+					t.Errorf("line %q is not console-encoded", line)
+				}
+			},
+		},
+		{
+			name: "logfmt encoding",
+			opts: []Option{WithEncoding("logfmt")},
+			check: func(t *testing.T, line string) {
+				if !strings.HasPrefix(line, "level=info ") {
+					t.Errorf("line %q is not logfmt-encoded", line)
+				}
+			},
+		},
+		{
+			name: "time layout",
+			opts: []Option{WithTimeLayout(time.Kitchen)},
+			check: func(t *testing.T, line string) {
+				ts := decodeLine(t, line)["ts"].(string)
+				if _, err := time.Parse(time.Kitchen, ts); err != nil {
+					t.Errorf("ts %q does not use the layout", ts)
+				}
+			},
+		},
+		{
+			name: "empty time layout",
+			opts: []Option{WithTimeLayout("")},
+			check: func(t *testing.T, line string) {
+				ts := decodeLine(t, line)["ts"].(string)
+				if _, err := time.Parse(time.RFC3339, ts); err != nil {
+					t.Errorf("ts %q is not RFC3339", ts)
+				}
+			},
+		},
+		{
+			name: "combined",
+			opts: []Option{WithLevel(zapcore.DebugLevel), WithEncoding("console"), WithTimeLayout("2006")},
+			check: func(t *testing.T, line string) {
+				if !strings.HasPrefix(line, time.Now().Format("2006")+"\tdebug\t") {
+					t.Errorf("line %q is not a console debug entry with a year timestamp", line)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.log")
+			logger, err := Build(append(tt.opts, WithOutputPaths(path))...)
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			logger.Debug("debug")
+			logger.Info("info")
+			_ = logger.Sync()
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, strings.SplitN(string(b), "\n", 2)[0])
+		})
+	}
+}
+
+func TestWithOutputPaths(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")}
+	logger, err := Build(WithOutputPaths(paths...))
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	logger.Info("both")
+	_ = logger.Sync()
+
+	for _, path := range paths {
+		if entries := readEntries(t, path); len(entries) != 1 {
+			t.Errorf("%s has %d entries, want 1", path, len(entries))
+		}
+	}
+}
+
+// decodeLine decodes a JSON entry.
+func decodeLine(t *testing.T, line string) map[string]interface{} {
+	t.Helper()
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("entry %q is not JSON: %v", line, err)
+	}
+	return entry
+}
+
+func TestWithEpochNanos(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		numeric bool
+	}{
+		{"epoch nanos", []Option{WithEpochNanos()}, true},
+		{"layout after", []Option{WithEpochNanos(), WithTimeLayout(time.RFC3339)}, false},
+		{"layout before", []Option{WithTimeLayout(time.RFC3339), WithEpochNanos()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.log")
+			logger, err := NewLogger(append(tt.opts, WithOutputPaths(path))...)
+			if err != nil {
+				t.Fatalf("NewLogger: %v", err)
+			}
+			before := time.Now().UnixNano()
+			logger.Info("fetched")
+			after := time.Now().UnixNano()
+			_ = logger.Sync()
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.UseNumber()
+			var entry map[string]interface{}
+			if err := dec.Decode(&entry); err != nil {
+				t.Fatal(err)
+			}
+			n, ok := entry["ts"].(json.Number)
+			if ok != tt.numeric {
+				t.Fatalf("ts = %v (%T), want numeric: %v", entry["ts"], entry["ts"], tt.numeric)
+			}
+			if !tt.numeric {
+				return
+			}
+			ts, err := n.Int64()
+			if err != nil {
+				t.Fatalf("ts %s is not an integer", n)
+			}
+			if ts < before || ts > after {
+				t.Errorf("ts = %d, want nanoseconds between %d and %d", ts, before, after)
+			}
+		})
+	}
+}
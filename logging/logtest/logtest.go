@@ -0,0 +1,50 @@
+// Package logtest helps tests capture and check what code under test logs.
+package logtest
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// NewTestLogger returns a logger that records every entry, at DebugLevel and
+// above, into the returned ObservedLogs instead of writing it anywhere.
+func NewTestLogger() (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(core), logs
+}
+
+// AssertLogged fails t unless logs holds at least one entry at level with
+// message msg.
+func AssertLogged(t testing.TB, logs *observer.ObservedLogs, level zapcore.Level, msg string) {
+	t.Helper()
+	for _, e := range logs.All() {
+		if e.Level == level && e.Message == msg {
+			return
+		}
+	}
+	t.Errorf("no %s entry with message %q was logged; got:", level, msg)
+	for _, e := range logs.All() {
+		t.Errorf("  %s %q %v", e.Level, e.Message, e.ContextMap())
+	}
+}
+
+// FieldValue returns the value of the field named key in entry, with the
+// type zap encodes it with: a string for zap.String, an int64 for zap.Int,
+// a bool for zap.Bool, a time.Duration for zap.Duration and so on. The
+// second result is false if entry has no such field.
+func FieldValue(entry observer.LoggedEntry, key string) (interface{}, bool) {
+	for i := len(entry.Context) - 1; i >= 0; i-- {
+		f := entry.Context[i]
+		if f.Key != key {
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		v, ok := enc.Fields[key]
+		return v, ok
+	}
+	return nil, false
+}
@@ -0,0 +1,95 @@
+package logtest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingTB is a testing.TB that records failures instead of reporting
+// them, for checking that AssertLogged fails.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestNewTestLogger(t *testing.T) {
+	logger, logs := NewTestLogger()
+	logger.Debug("debug")
+	logger.Info("fetched", zap.String("url", "http://marmotedu.com"))
+
+	if logs.Len() != 2 {
+		t.Fatalf("got %d entries, want the debug and info entries", logs.Len())
+	}
+	AssertLogged(t, logs, zapcore.DebugLevel, "debug")
+	AssertLogged(t, logs, zapcore.InfoLevel, "fetched")
+	if got, _ := FieldValue(logs.All()[1], "url"); got != "http://marmotedu.com" {
+		t.Errorf("url = %v, want http://marmotedu.com", got)
+	}
+}
+
+func TestAssertLogged(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    zapcore.Level
+		msg      string
+		wantFail bool
+	}{
+		{"match", zapcore.InfoLevel, "fetched", false},
+		{"wrong level", zapcore.WarnLevel, "fetched", true},
+		{"wrong message", zapcore.InfoLevel, "failed", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := NewTestLogger()
+			logger.Info("fetched")
+
+			tb := &recordingTB{TB: t}
+			AssertLogged(tb, logs, tt.level, tt.msg)
+			if failed := len(tb.errors) > 0; failed != tt.wantFail {
+				t.Errorf("failed = %v, want %v: %v", failed, tt.wantFail, tb.errors)
+			}
+		})
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	logger, logs := NewTestLogger()
+	logger.Info("fetched",
+		zap.String("url", "http://marmotedu.com"),
+		zap.Int("attempt", 3),
+		zap.Bool("cached", true),
+		zap.Duration("backoff", time.Second),
+		zap.Int("attempt", 4),
+	)
+	entry := logs.All()[0]
+
+	tests := []struct {
+		key    string
+		want   interface{}
+		wantOK bool
+	}{
+		{"url", "http://marmotedu.com", true},
+		{"attempt", int64(4), true},
+		{"cached", true, true},
+		{"backoff", time.Second, true},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok := FieldValue(entry, tt.key)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("FieldValue = %v (%T), %v, want %v (%T), %v", got, got, ok, tt.want, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	lokiPushPath = "/loki/api/v1/push"
+	// lokiQueueBatches is how many batches may wait to be pushed before the
+	// syncer starts dropping lines.
+	lokiQueueBatches = 8
+)
+
+var errLokiClosed = errors.New("logging: loki syncer is closed")
+
+// LokiSyncer is the WriteSyncer returned by NewLokiSyncer.
+type LokiSyncer struct {
+	url    string
+	labels map[string]string
+	client *http.Client
+	batch  *batcher[lokiLine]
+}
+
+type lokiLine struct {
+	ts   string
+	line string
+}
+
+// NewLokiSyncer returns a WriteSyncer that pushes every written line to the
+// Loki server at endpoint, tagged with labels. Lines are sent through Loki's
+// JSON push API in batches of up to batchSize, or whatever has accumulated
+// every flushInterval, and immediately on Sync.
+//
+// Writes never block: when the pushes cannot keep up and the queue is full,
+// lines are dropped and counted, see Dropped. Lines from a push Loki rejects
+// are counted as dropped too.
+//
+// The returned syncer is a *LokiSyncer; Close it to stop its background push
+// loop.
+func NewLokiSyncer(endpoint string, labels map[string]string, batchSize int, flushInterval time.Duration) (zapcore.WriteSyncer, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("logging: loki endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("logging: loki endpoint %q must be an http or https URL", endpoint)
+	}
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	if !strings.HasSuffix(u.Path, lokiPushPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + lokiPushPath
+	}
+
+	// Copied, as the caller may go on using the map while pushes read it.
+	own := make(map[string]string, len(labels))
+	for k, v := range labels {
+		own[k] = v
+	}
+	s := &LokiSyncer{
+		url:    u.String(),
+		labels: own,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	s.batch = newBatcher(batchSize, lokiQueueBatches, flushInterval, errLokiClosed, s.push)
+	return s, nil
+}
+
+// Write queues one encoded entry. It never fails: a full queue drops the line.
+func (s *LokiSyncer) Write(p []byte) (int, error) {
+	line := lokiLine{
+		ts:   strconv.FormatInt(time.Now().UnixNano(), 10),
+		line: strings.TrimRight(string(p), "\n"),
+	}
+	s.batch.add(line)
+	return len(p), nil
+}
+
+// Sync pushes every queued line and reports whether Loki accepted them.
+func (s *LokiSyncer) Sync() error {
+	return s.batch.sync()
+}
+
+// Dropped returns the number of lines that never made it to Loki.
+func (s *LokiSyncer) Dropped() int64 {
+	return s.batch.dropped.Load()
+}
+
+// Close pushes the queued lines and stops the background push loop. It
+// returns once the loop has exited.
+func (s *LokiSyncer) Close() error {
+	return s.batch.close()
+}
+
+// lokiPush is the body of a request to Loki's push API.
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (s *LokiSyncer) push(batch []lokiLine) error {
+	values := make([][2]string, len(batch))
+	for i, l := range batch {
+		values[i] = [2]string{l.ts, l.line}
+	}
+	body, err := json.Marshal(lokiPush{Streams: []lokiStream{{Stream: s.labels, Values: values}}})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("logging: loki push: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("logging: loki push: unexpected status %s", resp.Status)
+	}
+	return nil
+}
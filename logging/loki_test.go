@@ -0,0 +1,230 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lokiServer records the pushes it receives, answering them with status.
+type lokiServer struct {
+	*httptest.Server
+	status int
+
+	mu     sync.Mutex
+	pushes []lokiPush
+	paths  []string
+	pushed chan struct{}
+}
+
+func newLokiServer(t *testing.T, status int) *lokiServer {
+	s := &lokiServer{status: status, pushed: make(chan struct{}, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var push lokiPush
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			t.Errorf("push body: %v", err)
+		}
+		s.mu.Lock()
+		s.pushes = append(s.pushes, push)
+		s.paths = append(s.paths, r.URL.Path)
+		s.mu.Unlock()
+		w.WriteHeader(s.status)
+		s.pushed <- struct{}{}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// values returns the lines of every push received so far.
+func (s *lokiServer) values() [][2]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var values [][2]string
+	for _, p := range s.pushes {
+		for _, st := range p.Streams {
+			values = append(values, st.Values...)
+		}
+	}
+	return values
+}
+
+func TestLokiSyncer(t *testing.T) {
+	tests := []struct {
+		name          string
+		batchSize     int
+		flushInterval time.Duration
+		lines         int
+		// sync calls Sync instead of waiting for a push.
+		sync bool
+	}{
+		{"full batch", 2, time.Hour, 2, false},
+		{"timer", 10, 10 * time.Millisecond, 1, false},
+		{"sync", 10, time.Hour, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newLokiServer(t, http.StatusNoContent)
+			ws, err := NewLokiSyncer(srv.URL, map[string]string{"app": "demo"}, tt.batchSize, tt.flushInterval)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ws.(*LokiSyncer).Close()
+
+			before := time.Now().UnixNano()
+			for i := 0; i < tt.lines; i++ {
+				ws.Write([]byte(`{"msg":"line ` + strconv.Itoa(i) + `"}` + "\n"))
+			}
+			if tt.sync {
+				if err := ws.Sync(); err != nil {
+					t.Fatalf("Sync: %v", err)
+				}
+			} else {
+				select {
+				case <-srv.pushed:
+				case <-time.After(time.Second):
+					t.Fatal("nothing was pushed")
+				}
+			}
+
+			values := srv.values()
+			if len(values) != tt.lines {
+				t.Fatalf("got %d lines, want %d", len(values), tt.lines)
+			}
+			for i, v := range values {
+				ts, err := strconv.ParseInt(v[0], 10, 64)
+				if err != nil || ts < before {
+					t.Errorf("line %d has timestamp %q, want nanoseconds since %d", i, v[0], before)
+				}
+				if want := `{"msg":"line ` + strconv.Itoa(i) + `"}`; v[1] != want {
+					t.Errorf("line %d = %q, want %q", i, v[1], want)
+				}
+			}
+			srv.mu.Lock()
+			defer srv.mu.Unlock()
+			if srv.paths[0] != lokiPushPath {
+				t.Errorf("pushed to %q, want %q", srv.paths[0], lokiPushPath)
+			}
+			if got := srv.pushes[0].Streams[0].Stream["app"]; got != "demo" {
+				t.Errorf("label app = %q, want demo", got)
+			}
+		})
+	}
+}
+
+func TestLokiSyncerRejected(t *testing.T) {
+	srv := newLokiServer(t, http.StatusBadRequest)
+	ws, err := NewLokiSyncer(srv.URL, nil, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ws.(*LokiSyncer)
+	defer s.Close()
+
+	ws.Write([]byte("a\n"))
+	ws.Write([]byte("b\n"))
+	if err := ws.Sync(); err == nil {
+		t.Error("Sync succeeded although Loki rejected the push")
+	}
+	if n := s.Dropped(); n != 2 {
+		t.Errorf("Dropped = %d, want 2", n)
+	}
+}
+
+func TestLokiSyncerFullQueue(t *testing.T) {
+	received, release := make(chan struct{}), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer srv.Close()
+	ws, err := NewLokiSyncer(srv.URL, nil, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ws.(*LokiSyncer)
+
+	// The first line is pushed at once, and the push loop waits for the
+	// server while the rest fill the queue.
+	ws.Write([]byte("first\n"))
+	<-received
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < lokiQueueBatches+5; i++ {
+			ws.Write([]byte("line\n"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked on a full queue")
+	}
+	if n := s.Dropped(); n != 5 {
+		t.Errorf("Dropped = %d, want 5", n)
+	}
+
+	go func() {
+		for range received {
+		}
+	}()
+	close(release)
+	s.Close()
+}
+
+func TestNewLokiSyncerInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"://bad", "ftp://loki:3100", "loki:3100"} {
+		if _, err := NewLokiSyncer(endpoint, nil, 10, time.Second); err == nil {
+			t.Errorf("NewLokiSyncer(%q) succeeded", endpoint)
+		}
+	}
+}
+
+func TestLokiSyncerClosed(t *testing.T) {
+	srv := newLokiServer(t, http.StatusNoContent)
+	ws, err := NewLokiSyncer(srv.URL+"/", nil, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ws.(*LokiSyncer)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if err := ws.Sync(); err == nil {
+		t.Error("Sync succeeded after Close")
+	}
+	select {
+	case <-s.batch.stopped:
+	default:
+		t.Error("push loop still running after Close")
+	}
+}
+
+func TestLokiSyncerCopiesLabels(t *testing.T) {
+	srv := newLokiServer(t, http.StatusNoContent)
+	labels := map[string]string{"app": "api"}
+	ws, err := NewLokiSyncer(srv.URL, labels, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.(*LokiSyncer).Close() })
+	labels["app"] = "changed"
+	labels["env"] = "prod"
+
+	ws.Write([]byte("line\n"))
+	if err := ws.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	got := srv.pushes[0].Streams[0].Stream
+	if len(got) != 1 || got["app"] != "api" {
+		t.Errorf("stream labels = %v, want map[app:api]", got)
+	}
+}
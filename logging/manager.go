@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// Manager keeps track of a program's named loggers, such as "app", "audit"
+// and "access", so that they can be looked up in one place and flushed
+// together on shutdown. It is safe for concurrent use.
+type Manager struct {
+	mu      sync.RWMutex
+	loggers map[string]*zap.Logger
+}
+
+// NewManager returns a Manager with no loggers.
+func NewManager() *Manager {
+	return &Manager{loggers: make(map[string]*zap.Logger)}
+}
+
+// Register stores l under name. A logger already registered under name is
+// replaced and synced, and the error from that sync, if any, is returned.
+func (m *Manager) Register(name string, l *zap.Logger) error {
+	m.mu.Lock()
+	old := m.loggers[name]
+	m.loggers[name] = l
+	m.mu.Unlock()
+
+	if old == nil || old == l {
+		return nil
+	}
+	if err := SafeSync(old); err != nil {
+		return fmt.Errorf("logging: sync replaced logger %q: %w", name, err)
+	}
+	return nil
+}
+
+// Get returns the logger registered under name, or a no-op logger if there
+// is none, so the result can always be logged to.
+func (m *Manager) Get(name string) *zap.Logger {
+	m.mu.RLock()
+	l := m.loggers[name]
+	m.mu.RUnlock()
+	if l == nil {
+		return zap.NewNop()
+	}
+	return l
+}
+
+// SyncAll syncs every registered logger with SafeSync. A failing logger does
+// not stop the others from being synced; all failures are returned together.
+func (m *Manager) SyncAll() error {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.loggers))
+	for name := range m.loggers {
+		names = append(names, name)
+	}
+	loggers := make(map[string]*zap.Logger, len(m.loggers))
+	for name, l := range m.loggers {
+		loggers[name] = l
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(names)
+	var errs error
+	for _, name := range names {
+		if err := SafeSync(loggers[name]); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("logging: sync logger %q: %w", name, err))
+		}
+	}
+	return errs
+}
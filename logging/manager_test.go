@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestManagerSyncAll(t *testing.T) {
+	tests := []struct {
+		name string
+		// failing names the logger whose sync fails, if any.
+		failing string
+	}{
+		{"all succeed", ""},
+		{"one fails", "audit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager()
+			syncers := map[string]*fakeSyncer{}
+			for _, name := range []string{"access", "app", "audit"} {
+				ws := newFakeSyncer()
+				if name == tt.failing {
+					ws.err = errors.New("disk full")
+				}
+				syncers[name] = ws
+				if err := m.Register(name, newSyncerLogger(ws)); err != nil {
+					t.Fatalf("Register(%q): %v", name, err)
+				}
+			}
+
+			err := m.SyncAll()
+
+			for name, ws := range syncers {
+				if ws.syncCount() != 1 {
+					t.Errorf("%s synced %d times, want 1", name, ws.syncCount())
+				}
+			}
+			if tt.failing == "" {
+				if err != nil {
+					t.Errorf("SyncAll = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), `"`+tt.failing+`"`) {
+				t.Errorf("SyncAll = %v, want an error naming %q", err, tt.failing)
+			}
+		})
+	}
+}
+
+func TestManagerRegister(t *testing.T) {
+	m := NewManager()
+	old, replacement := newFakeSyncer(), newFakeSyncer()
+	if err := m.Register("app", newSyncerLogger(old)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register("app", newSyncerLogger(replacement)); err != nil {
+		t.Fatal(err)
+	}
+
+	if old.syncCount() != 1 {
+		t.Errorf("replaced logger synced %d times, want 1", old.syncCount())
+	}
+	m.Get("app").Info("fetched")
+	if old.String() != "" || !strings.Contains(replacement.String(), "fetched") {
+		t.Error("Get did not return the replacement logger")
+	}
+}
+
+func TestManagerGetMissing(t *testing.T) {
+	l := NewManager().Get("missing")
+	if l == nil || l.Core().Enabled(zapcore.FatalLevel) {
+		t.Errorf("Get = %v, want a no-op logger", l)
+	}
+}
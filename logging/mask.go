@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"regexp"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultMask replaces the matches of WithRegexMask patterns.
+const defaultMask = "****"
+
+// WithRegexMask replaces every match of patterns in the message and in every
+// string or byte string field with "****", for sensitive values such as card
+// numbers that end up inside free text rather than under a known key; see
+// WithRedactedKeys for those. Other field types are left alone, so the
+// patterns only run on string content.
+func WithRegexMask(patterns ...*regexp.Regexp) Option {
+	return WithRegexMaskString(defaultMask, patterns...)
+}
+
+// WithRegexMaskString is WithRegexMask with mask as the replacement. The mask
+// is used literally, without expanding $ references.
+func WithRegexMaskString(mask string, patterns ...*regexp.Regexp) Option {
+	return func(o *options) {
+		if len(patterns) == 0 {
+			return
+		}
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &maskCore{Core: core, patterns: patterns, mask: mask}
+		})
+	}
+}
+
+type maskCore struct {
+	zapcore.Core
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+func (c *maskCore) With(fields []zapcore.Field) zapcore.Core {
+	return &maskCore{Core: c.Core.With(c.maskFields(fields)), patterns: c.patterns, mask: c.mask}
+}
+
+func (c *maskCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *maskCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if s, ok := c.maskString(ent.Message); ok {
+		ent.Message = s
+	}
+	return c.Core.Write(ent, c.maskFields(fields))
+}
+
+// maskFields returns fields with matches masked, copying the slice only if
+// one of them needs it.
+func (c *maskCore) maskFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		var masked zapcore.Field
+		switch f.Type {
+		case zapcore.StringType:
+			s, ok := c.maskString(f.String)
+			if !ok {
+				continue
+			}
+			masked = zap.String(f.Key, s)
+		case zapcore.ByteStringType:
+			b, ok := c.maskBytes(f.Interface.([]byte))
+			if !ok {
+				continue
+			}
+			masked = zap.ByteString(f.Key, b)
+		default:
+			continue
+		}
+		if out == nil {
+			out = append([]zapcore.Field(nil), fields...)
+		}
+		out[i] = masked
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (c *maskCore) maskString(s string) (string, bool) {
+	changed := false
+	for _, re := range c.patterns {
+		if re.MatchString(s) {
+			s = re.ReplaceAllLiteralString(s, c.mask)
+			changed = true
+		}
+	}
+	return s, changed
+}
+
+// maskBytes is maskString for byte strings, matching them in place so that
+// only a value that is masked is copied. b itself is left alone.
+func (c *maskCore) maskBytes(b []byte) ([]byte, bool) {
+	changed := false
+	for _, re := range c.patterns {
+		if re.Match(b) {
+			b = re.ReplaceAllLiteral(b, []byte(c.mask))
+			changed = true
+		}
+	}
+	return b, changed
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+var cardNumber = regexp.MustCompile(`\b\d{16}\b`)
+
+func TestWithRegexMask(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		log  func(l *zap.Logger)
+		want map[string]interface{}
+	}{
+		{
+			name: "message",
+			opts: []Option{WithRegexMask(cardNumber)},
+			log:  func(l *zap.Logger) { l.Info("charging card 4111111111111111 now") },
+			want: map[string]interface{}{"msg": "charging card **** now"},
+		},
+		{
+			name: "fields",
+			opts: []Option{WithRegexMask(cardNumber)},
+			log: func(l *zap.Logger) {
+				l.With(zap.String("card", "4111111111111111")).Info("charged",
+					zap.ByteString("raw", []byte("card=5500000000000004")),
+					zap.Int64("amount", 4111111111111111),
+					zap.String("order", "A-17"))
+			},
+			want: map[string]interface{}{
+				"card":   "****",
+				"raw":    "card=****",
+				"amount": float64(4111111111111111),
+				"order":  "A-17",
+			},
+		},
+		{
+			name: "several patterns and a custom mask",
+			opts: []Option{WithRegexMaskString("[masked]", cardNumber, regexp.MustCompile(`cvv=\d{3}`))},
+			log:  func(l *zap.Logger) { l.Info("4111111111111111 cvv=123") },
+			want: map[string]interface{}{"msg": "[masked] [masked]"},
+		},
+		{
+			name: "literal mask",
+			opts: []Option{WithRegexMaskString("$1", cardNumber)},
+			log:  func(l *zap.Logger) { l.Info("card 4111111111111111") },
+			want: map[string]interface{}{"msg": "card $1"},
+		},
+		{
+			name: "no match",
+			opts: []Option{WithRegexMask(cardNumber)},
+			log:  func(l *zap.Logger) { l.Info("order 411111111111111") },
+			want: map[string]interface{}{"msg": "order 411111111111111"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, tt.opts...)
+			tt.log(logger)
+
+			entry := entries()[0]
+			for key, v := range tt.want {
+				if entry[key] != v {
+					t.Errorf("%s = %v, want %v", key, entry[key], v)
+				}
+			}
+		})
+	}
+}
+
+func TestMaskFieldsByteString(t *testing.T) {
+	c := &maskCore{patterns: []*regexp.Regexp{cardNumber}, mask: "[masked]"}
+	clean := []zap.Field{zap.ByteString("body", []byte(strings.Repeat("order 42 ", 10)))}
+	if allocs := testing.AllocsPerRun(100, func() { c.maskFields(clean) }); allocs != 0 {
+		t.Errorf("unmatched byte string: %v allocs, want 0", allocs)
+	}
+
+	b := []byte("card 4111111111111111")
+	got := c.maskFields([]zap.Field{zap.ByteString("body", b)})
+	if want := "card [masked]"; string(got[0].Interface.([]byte)) != want {
+		t.Errorf("body = %q, want %q", got[0].Interface, want)
+	}
+	if string(b) != "card 4111111111111111" {
+		t.Errorf("caller's bytes changed to %q", b)
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewMetricsCore wraps base so that every entry it actually writes increments
+// counter, labeled by the entry's level ("info", "error", ...). Entries below
+// the configured level, or dropped by sampling, are not counted.
+//
+// counter must have a single "level" label and is registered by the caller,
+// typically with their own prometheus.Registry:
+//
+//	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+//		Name: "log_entries_total",
+//		Help: "Number of log entries written, by level.",
+//	}, []string{"level"})
+func NewMetricsCore(base zapcore.Core, counter *prometheus.CounterVec) zapcore.Core {
+	return &metricsCore{Core: base, counter: counter}
+}
+
+// WithMetrics counts the logger's entries with NewMetricsCore.
+func WithMetrics(counter *prometheus.CounterVec) Option {
+	return func(o *options) {
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return NewMetricsCore(core, counter)
+		})
+	}
+}
+
+type metricsCore struct {
+	zapcore.Core
+	counter *prometheus.CounterVec
+}
+
+func (c *metricsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &metricsCore{Core: c.Core.With(fields), counter: c.counter}
+}
+
+func (c *metricsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *metricsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if err := c.Core.Write(ent, fields); err != nil {
+		return err
+	}
+	c.counter.WithLabelValues(ent.Level.String()).Inc()
+	return nil
+}
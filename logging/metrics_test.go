@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewMetricsCore(t *testing.T) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_entries_total",
+		Help: "Number of log entries written, by level.",
+	}, []string{"level"})
+	base, _ := observer.New(zapcore.WarnLevel)
+	logger := zap.New(NewMetricsCore(base, counter))
+
+	logger.Info("below the level")
+	for i := 0; i < 2; i++ {
+		logger.Warn("warn")
+	}
+	for i := 0; i < 3; i++ {
+		logger.With(zap.Int("attempt", i)).Error("error")
+	}
+
+	tests := []struct {
+		level string
+		want  float64
+	}{
+		{"info", 0},
+		{"warn", 2},
+		{"error", 3},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(counter.WithLabelValues(tt.level)); got != tt.want {
+			t.Errorf("%s count = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// statusRecorder wraps an http.ResponseWriter to remember the status code and
+// the number of body bytes written by the handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush sends the buffered response to the client, for streaming handlers
+// such as server-sent events. It does nothing if the wrapped writer cannot
+// flush.
+func (r *statusRecorder) Flush() {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack hands the connection over to the handler, as websocket upgrades
+// need, and fails if the wrapped writer does not support it.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped writer, so that http.ResponseController reaches
+// the features statusRecorder does not forward itself.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// MiddlewareOption configures LoggingMiddleware.
+type MiddlewareOption func(*middlewareOptions)
+
+type middlewareOptions struct {
+	accessLog bool
+}
+
+// WithAccessLog tags each request entry with an access=true field and adds
+// the request URI, protocol and basic-auth user next to the usual fields,
+// which is everything a Common Log Format line needs. Pair it with the
+// WithCLFAccessLog logger option to get those entries written as CLF.
+func WithAccessLog() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.accessLog = true
+	}
+}
+
+// LoggingMiddleware logs one entry per request once the wrapped handler has
+// returned. Responses with a 5xx status are logged at ErrorLevel, everything
+// else at InfoLevel.
+//
+// A panic in the wrapped handler is logged at ErrorLevel together with its
+// stacktrace and then re-raised, so the server's own recovery still applies.
+func LoggingMiddleware(l *zap.Logger, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	var o middlewareOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+
+			defer func() {
+				if p := recover(); p != nil {
+					l.Error("http request panicked",
+						zap.String("method", r.Method),
+						zap.String("path", r.URL.Path),
+						zap.Duration("duration", time.Since(start)),
+						zap.String("remote_addr", r.RemoteAddr),
+						zap.Any("panic", p),
+						zap.Stack("stacktrace"),
+					)
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", rec.status),
+				zap.Int("bytes", rec.bytes),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("remote_addr", r.RemoteAddr),
+			}
+			if o.accessLog {
+				fields = append(fields,
+					zap.Bool(accessMarkerKey, true),
+					zap.String("request_uri", r.URL.RequestURI()),
+					zap.String("proto", r.Proto),
+				)
+				if user, _, ok := r.BasicAuth(); ok {
+					fields = append(fields, zap.String("user", user))
+				}
+			}
+			if rec.status >= http.StatusInternalServerError {
+				l.Error("http request", fields...)
+				return
+			}
+			l.Info("http request", fields...)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		wantLevel zapcore.Level
+		status    int64
+		bytes     int64
+	}{
+		{
+			name:      "ok",
+			handler:   func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("hello")) },
+			wantLevel: zapcore.InfoLevel,
+			status:    http.StatusOK,
+			bytes:     5,
+		},
+		{
+			name:      "no body",
+			handler:   func(w http.ResponseWriter, r *http.Request) {},
+			wantLevel: zapcore.InfoLevel,
+			status:    http.StatusOK,
+		},
+		{
+			name: "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "nope", http.StatusNotFound)
+			},
+			wantLevel: zapcore.InfoLevel,
+			status:    http.StatusNotFound,
+			bytes:     5,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+				w.WriteHeader(http.StatusOK)
+			},
+			wantLevel: zapcore.ErrorLevel,
+			status:    http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := logtest.NewTestLogger()
+			h := LoggingMiddleware(logger)(tt.handler)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users?id=1", nil))
+
+			logtest.AssertLogged(t, logs, tt.wantLevel, "http request")
+			entry := logs.All()[0]
+			want := map[string]interface{}{
+				"method": http.MethodGet,
+				"path":   "/users",
+				"status": tt.status,
+				"bytes":  tt.bytes,
+			}
+			for key, v := range want {
+				if got, _ := logtest.FieldValue(entry, key); got != v {
+					t.Errorf("%s = %v, want %v", key, got, v)
+				}
+			}
+			if d, _ := logtest.FieldValue(entry, "duration"); d == nil || d.(time.Duration) <= 0 {
+				t.Errorf("duration = %v, want a positive latency", d)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddlewarePanic(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	h := LoggingMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("recovered %v, want the handler's panic", p)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	logtest.AssertLogged(t, logs, zapcore.ErrorLevel, "http request panicked")
+	if got, _ := logtest.FieldValue(logs.All()[0], "stacktrace"); got == "" {
+		t.Error("panic entry has no stacktrace")
+	}
+}
+
+func TestLoggingMiddlewareAccessLog(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	h := LoggingMiddleware(logger, WithAccessLog())(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	r := httptest.NewRequest(http.MethodGet, "/users?id=1", nil)
+	r.SetBasicAuth("frank", "secret")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	entry := logs.All()[0]
+	want := map[string]interface{}{
+		accessMarkerKey: true,
+		"request_uri":   "/users?id=1",
+		"proto":         "HTTP/1.1",
+		"user":          "frank",
+	}
+	for key, v := range want {
+		if got, _ := logtest.FieldValue(entry, key); got != v {
+			t.Errorf("%s = %v, want %v", key, got, v)
+		}
+	}
+}
+
+func TestStatusRecorderForwarding(t *testing.T) {
+	tests := []struct {
+		name string
+		use  func(t *testing.T, w http.ResponseWriter)
+		// status is the status logged for the request.
+		status int64
+		// flushed reports whether the underlying recorder was flushed.
+		flushed bool
+	}{
+		{
+			name: "flush",
+			use: func(t *testing.T, w http.ResponseWriter) {
+				w.(http.Flusher).Flush()
+			},
+			status:  http.StatusOK,
+			flushed: true,
+		},
+		{
+			name: "flush through ResponseController",
+			use: func(t *testing.T, w http.ResponseWriter) {
+				w.WriteHeader(http.StatusAccepted)
+				if err := http.NewResponseController(w).Flush(); err != nil {
+					t.Errorf("Flush: %v", err)
+				}
+			},
+			status:  http.StatusAccepted,
+			flushed: true,
+		},
+		{
+			name: "hijack unsupported",
+			use: func(t *testing.T, w http.ResponseWriter) {
+				if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+					t.Error("Hijack succeeded on a writer that cannot hijack")
+				}
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "unwrap",
+			use: func(t *testing.T, w http.ResponseWriter) {
+				u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+				if !ok {
+					t.Fatal("writer has no Unwrap method")
+				}
+				if _, ok := u.Unwrap().(*httptest.ResponseRecorder); !ok {
+					t.Errorf("Unwrap returned %T, want the wrapped recorder", u.Unwrap())
+				}
+			},
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := logtest.NewTestLogger()
+			h := LoggingMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.use(t, w)
+			}))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+			if rec.Flushed != tt.flushed {
+				t.Errorf("Flushed = %v, want %v", rec.Flushed, tt.flushed)
+			}
+			if got, _ := logtest.FieldValue(logs.All()[0], "status"); got != tt.status {
+				t.Errorf("status = %v, want %d", got, tt.status)
+			}
+		})
+	}
+}
+
+func TestStatusRecorderHijack(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	h := LoggingMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 204 No Content\r\n\r\n")
+		rw.Flush()
+	}))
+	// The middleware logs after the client has its response, so wait for
+	// it to return.
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		h.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	<-done
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("client got status %d, want the hijacked connection's 204", resp.StatusCode)
+	}
+	if got, _ := logtest.FieldValue(logs.All()[0], "status"); got != int64(http.StatusSwitchingProtocols) {
+		t.Errorf("status = %v, want %d", got, http.StatusSwitchingProtocols)
+	}
+}
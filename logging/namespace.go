@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Namespaced groups fields into a single field that nests them under ns:
+//
+//	logger.Info("fetched", logging.Namespaced("request",
+//		zap.String("url", url),
+//		zap.Int("attempt", 3),
+//	))
+//
+// logs {"msg":"fetched","request":{"url":"...","attempt":3}}. Unlike
+// zap.Namespace, which captures every field that follows it, only the given
+// fields are nested.
+func Namespaced(ns string, fields ...zap.Field) zap.Field {
+	return zap.Object(ns, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		return nil
+	}))
+}
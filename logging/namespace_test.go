@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestNamespaced(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []zap.Field
+		want   string
+	}{
+		{
+			name: "request",
+			fields: []zap.Field{
+				Namespaced("request",
+					zap.String("url", "http://marmotedu.com"),
+					zap.Int("attempt", 3),
+					zap.Duration("backoff", time.Second),
+				),
+				zap.String("service", "api"),
+			},
+			want: `"request":{"url":"http://marmotedu.com","attempt":3,"backoff":1},"service":"api"}`,
+		},
+		{
+			name:   "nested",
+			fields: []zap.Field{Namespaced("http", Namespaced("request", zap.String("method", "GET")))},
+			want:   `"http":{"request":{"method":"GET"}}}`,
+		},
+		{
+			name:   "empty",
+			fields: []zap.Field{Namespaced("request")},
+			want:   `"request":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newFakeSyncer()
+			newSyncerLogger(ws).Info("fetched", tt.fields...)
+
+			if got := strings.TrimSpace(ws.String()); !strings.HasSuffix(got, tt.want) {
+				t.Errorf("entry = %s, want it to end with %s", got, tt.want)
+			}
+		})
+	}
+}
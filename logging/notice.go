@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	severityKey    = "severity"
+	noticeSeverity = "notice"
+)
+
+// Notice logs msg as a notice: an event worth auditing that is not a
+// warning. zap has no level between Info and Warn, they are adjacent
+// integers, so a notice is an InfoLevel entry tagged "severity":"notice".
+// That tells it apart from plain Info entries, which have no severity, and
+// from Warn entries by their level. Being InfoLevel, notices are dropped by a
+// logger whose level is above Info; WithNoticeFilter turns them off on their
+// own.
+func Notice(l *zap.Logger, msg string, fields ...zap.Field) {
+	fields = append(fields[:len(fields):len(fields)], zap.String(severityKey, noticeSeverity))
+	helperLogger(l).Info(msg, fields...)
+}
+
+// WithNoticeFilter drops the entries logged with Notice while enabled returns
+// false, leaving every other InfoLevel entry alone. enabled is called for
+// each notice, so it can be backed by an atomic.Bool to switch notices on and
+// off at runtime.
+func WithNoticeFilter(enabled func() bool) Option {
+	return func(o *options) {
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &noticeFilterCore{Core: core, enabled: enabled}
+		})
+	}
+}
+
+type noticeFilterCore struct {
+	zapcore.Core
+	enabled func() bool
+}
+
+func (c *noticeFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &noticeFilterCore{Core: c.Core.With(fields), enabled: c.enabled}
+}
+
+func (c *noticeFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *noticeFilterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level == zapcore.InfoLevel && isNotice(fields) && !c.enabled() {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+func isNotice(fields []zapcore.Field) bool {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if f := fields[i]; f.Key == severityKey && f.Type == zapcore.StringType && f.String == noticeSeverity {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNotice(t *testing.T) {
+	logger, entries := newFileLogger(t, WithShortCaller())
+	want := here(1)
+	Notice(logger, "role granted", zap.String("user", "frank"))
+	logger.Info("info")
+	logger.Warn("warn")
+
+	got := entries()
+	tests := []struct {
+		level    string
+		severity interface{}
+	}{
+		{"info", "notice"},
+		{"info", nil},
+		{"warn", nil},
+	}
+	for i, tt := range tests {
+		if got[i]["level"] != tt.level || got[i]["severity"] != tt.severity {
+			t.Errorf("entry %d = %v, want level %s and severity %v", i, got[i], tt.level, tt.severity)
+		}
+	}
+	if got[0]["user"] != "frank" {
+		t.Errorf("user = %v, want the notice's own fields kept", got[0]["user"])
+	}
+	if got[0]["caller"] != want {
+		t.Errorf("caller = %v, want the call to Notice at %s", got[0]["caller"], want)
+	}
+}
+
+func TestWithNoticeFilter(t *testing.T) {
+	var enabled atomic.Bool
+	logger, entries := newFileLogger(t, WithNoticeFilter(enabled.Load))
+
+	Notice(logger, "dropped notice")
+	logger.Info("kept info")
+	enabled.Store(true)
+	Notice(logger.With(zap.String("service", "api")), "kept notice")
+
+	var msgs []string
+	for _, e := range entries() {
+		msgs = append(msgs, e["msg"].(string))
+	}
+	if want := []string{"kept info", "kept notice"}; !reflect.DeepEqual(msgs, want) {
+		t.Errorf("messages = %v, want %v", msgs, want)
+	}
+}
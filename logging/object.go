@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Object logs m under key through its MarshalLogObject method.
+//
+// zap.Any falls back to encoding/json reflection for structs, which allocates
+// for every field on every call. Implementing zapcore.ObjectMarshaler on the
+// type and logging it with Object instead writes each field straight into
+// the encoder with its concrete type:
+//
+//	func (u User) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+//		enc.AddString("name", u.Name)
+//		enc.AddInt("age", u.Age)
+//		return nil
+//	}
+//
+//	logger.Info("signed up", logging.Object("user", u))
+//
+// Being generic over the marshaler, Object also catches at compile time a
+// type that does not implement the interface, which zap.Any would quietly
+// reflect instead.
+func Object[T zapcore.ObjectMarshaler](key string, m T) zap.Field {
+	return zap.Object(key, m)
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestObject(t *testing.T) {
+	tests := []struct {
+		name  string
+		field zap.Field
+		want  map[string]interface{}
+	}{
+		{
+			name:  "marshaler",
+			field: Object("user", user),
+			want: map[string]interface{}{
+				"name":       "marmotedu",
+				"email":      "marmotedu@example.com",
+				"age":        float64(30),
+				"created_at": "2023-10-24T11:06:18Z",
+			},
+		},
+		{
+			name:  "reflection",
+			field: zap.Any("user", plainBenchUser(user)),
+			want: map[string]interface{}{
+				"Name":      "marmotedu",
+				"Email":     "marmotedu@example.com",
+				"Age":       float64(30),
+				"CreatedAt": "2023-10-24T11:06:18Z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newFakeSyncer()
+			cfg := zap.NewProductionEncoderConfig()
+			cfg.EncodeTime = zapcore.RFC3339TimeEncoder
+			logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(cfg), ws, zapcore.DebugLevel))
+
+			logger.Info("signed up", tt.field)
+
+			got, ok := decodeLine(t, ws.String())["user"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("user is not an object: %s", ws)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("user = %v, want %v", got, tt.want)
+			}
+			for key, v := range tt.want {
+				if got[key] != v {
+					t.Errorf("%s = %v, want %v", key, got[key], v)
+				}
+			}
+		})
+	}
+}
+
+func TestObjectType(t *testing.T) {
+	if f := Object("user", user); f.Type != zapcore.ObjectMarshalerType {
+		t.Errorf("Type = %v, want ObjectMarshalerType", f.Type)
+	}
+	if f := zap.Any("user", plainBenchUser(user)); f.Type != zapcore.ReflectType {
+		t.Errorf("zap.Any Type = %v, want ReflectType for a struct without MarshalLogObject", f.Type)
+	}
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// prettyJSONEncoding is the encoding WithPrettyJSON swaps "json" for.
+const prettyJSONEncoding = "logging-pretty-json"
+
+var prettyPool = buffer.NewPool()
+
+func init() {
+	_ = zap.RegisterEncoder(prettyJSONEncoding, func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return newPrettyJSONEncoder(cfg), nil
+	})
+}
+
+// WithPrettyJSON indents the JSON output by two spaces with one field per
+// line, which is easier to read in a terminal while developing. Every entry
+// is still a single valid JSON document, but no longer a single line, so
+// keep it out of production, where log shippers expect one entry per line.
+// It does nothing unless the encoding is "json".
+func WithPrettyJSON() Option {
+	return func(o *options) {
+		o.prettyJSON = true
+	}
+}
+
+func newPrettyJSONEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	lineEnding := cfg.LineEnding
+	if lineEnding == "" {
+		lineEnding = zapcore.DefaultLineEnding
+	}
+	return prettyJSONEncoder{Encoder: zapcore.NewJSONEncoder(cfg), lineEnding: lineEnding}
+}
+
+// prettyJSONEncoder indents what the JSON encoder it wraps produces.
+type prettyJSONEncoder struct {
+	zapcore.Encoder
+	lineEnding string
+}
+
+func (e prettyJSONEncoder) Clone() zapcore.Encoder {
+	return prettyJSONEncoder{Encoder: e.Encoder.Clone(), lineEnding: e.lineEnding}
+}
+
+func (e prettyJSONEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer line.Free()
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimRight(line.Bytes(), "\r\n"), "", "  "); err != nil {
+		return nil, err
+	}
+	out := prettyPool.Get()
+	out.AppendBytes(indented.Bytes())
+	out.AppendString(e.lineEnding)
+	return out, nil
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// logTwice logs two entries with a Build logger using opts and returns the
+// output.
+func logTwice(t *testing.T, opts ...Option) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.log")
+	logger, err := Build(append(opts, WithOutputPaths(path))...)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	logger.Info("first", zap.String("url", "http://marmotedu.com"))
+	logger.Info("second", zap.Strings("tags", []string{"a", "b"}))
+	_ = logger.Sync()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestWithPrettyJSON(t *testing.T) {
+	out := logTwice(t, WithPrettyJSON())
+
+	if !strings.Contains(out, "{\n  \"level\": \"info\",\n") {
+		t.Errorf("output %q is not indented by two spaces, one field per line", out)
+	}
+	dec := json.NewDecoder(strings.NewReader(out))
+	var msgs []string
+	for {
+		var entry map[string]interface{}
+		if err := dec.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("output %q is not valid JSON: %v", out, err)
+		}
+		msgs = append(msgs, entry["msg"].(string))
+	}
+	if len(msgs) != 2 || msgs[0] != "first" || msgs[1] != "second" {
+		t.Errorf("decoded messages %v, want [first second]", msgs)
+	}
+}
+
+func TestWithPrettyJSONConsole(t *testing.T) {
+	out := logTwice(t, WithPrettyJSON(), WithEncoding("console"))
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "\tinfo\t") {
+		t.Errorf("output %q is not plain console output", out)
+	}
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitSweepInterval is how often idle per-key limiters are looked for.
+const rateLimitSweepInterval = time.Minute
+
+// WithRateLimitedKey limits how often entries sharing the same value of the
+// keyField field are written: each value gets a token bucket allowing rate
+// entries per second with bursts of up to burst entries, and entries over the
+// limit are dropped. The field may be passed to the log call or attached
+// earlier with With. Entries without the field are not limited.
+//
+// A key's limiter is forgotten once it has been idle long enough to refill
+// completely, since a fresh limiter would behave the same; this keeps memory
+// bounded when key values are unbounded, such as user IDs.
+func WithRateLimitedKey(keyField string, rate, burst int) Option {
+	return func(o *options) {
+		limits := newKeyedLimiters(rate, burst)
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &rateLimitCore{Core: core, keyField: keyField, limits: limits}
+		})
+	}
+}
+
+type keyedLimiters struct {
+	limit rate.Limit
+	burst int
+	// idle is how long a limiter takes to refill its whole burst.
+	idle time.Duration
+
+	mu        sync.Mutex
+	limiters  map[string]*keyedLimiter
+	lastSweep time.Time
+}
+
+type keyedLimiter struct {
+	*rate.Limiter
+	lastUsed time.Time
+}
+
+func newKeyedLimiters(r, burst int) *keyedLimiters {
+	idle := time.Duration(math.MaxInt64)
+	if r > 0 {
+		idle = time.Duration(burst) * time.Second / time.Duration(r)
+	}
+	return &keyedLimiters{
+		limit:     rate.Limit(r),
+		burst:     burst,
+		idle:      idle,
+		limiters:  make(map[string]*keyedLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+func (k *keyedLimiters) allow(key string) bool {
+	now := time.Now()
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if now.Sub(k.lastSweep) >= rateLimitSweepInterval {
+		for key, l := range k.limiters {
+			if now.Sub(l.lastUsed) > k.idle {
+				delete(k.limiters, key)
+			}
+		}
+		k.lastSweep = now
+	}
+
+	l, ok := k.limiters[key]
+	if !ok {
+		l = &keyedLimiter{Limiter: rate.NewLimiter(k.limit, k.burst)}
+		k.limiters[key] = l
+	}
+	l.lastUsed = now
+	return l.AllowN(now, 1)
+}
+
+type rateLimitCore struct {
+	zapcore.Core
+	keyField string
+	limits   *keyedLimiters
+	// key is the value of keyField when it was attached with With.
+	key    string
+	hasKey bool
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	if key, ok := fieldValueString(fields, c.keyField); ok {
+		clone.key, clone.hasKey = key, true
+	}
+	return &clone
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key, ok := fieldValueString(fields, c.keyField)
+	if !ok {
+		key, ok = c.key, c.hasKey
+	}
+	if ok && !c.limits.allow(key) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// fieldValueString returns the value of the last field named key, formatted
+// as a string.
+func fieldValueString(fields []zapcore.Field, key string) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if f.Key != key {
+			continue
+		}
+		if f.Type == zapcore.StringType {
+			return f.String, true
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		return fmt.Sprint(enc.Fields[key]), true
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWithRateLimitedKey(t *testing.T) {
+	const rate, burst = 5, 5
+	tests := []struct {
+		name string
+		log  func(l *zap.Logger, i int)
+		// The number of the 20 entries written is between min and max.
+		min, max int
+	}{
+		{
+			name: "same key",
+			log:  func(l *zap.Logger, i int) { l.Info("request", zap.String("user", "u1")) },
+			min:  burst,
+			max:  burst + rate,
+		},
+		{
+			name: "key attached with With",
+			log:  func(l *zap.Logger, i int) { l.With(zap.String("user", "u1")).Info("request") },
+			min:  burst,
+			max:  burst + rate,
+		},
+		{
+			name: "numeric key",
+			log:  func(l *zap.Logger, i int) { l.Info("request", zap.Int("user", 42)) },
+			min:  burst,
+			max:  burst + rate,
+		},
+		{
+			name: "distinct keys",
+			log:  func(l *zap.Logger, i int) { l.Info("request", zap.String("user", fmt.Sprint(i))) },
+			min:  20,
+			max:  20,
+		},
+		{
+			name: "no key",
+			log:  func(l *zap.Logger, i int) { l.Info("request") },
+			min:  20,
+			max:  20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithSampling(0, 0), WithRateLimitedKey("user", rate, burst))
+			for i := 0; i < 20; i++ {
+				tt.log(logger, i)
+			}
+			if n := len(entries()); n < tt.min || n > tt.max {
+				t.Errorf("%d entries were written, want %d to %d", n, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestKeyedLimitersForgetIdleKeys(t *testing.T) {
+	limits := newKeyedLimiters(5, 5)
+	limits.allow("stale")
+	limits.allow("fresh")
+
+	// Age the stale key past the refill time and make a sweep due.
+	limits.limiters["stale"].lastUsed = time.Now().Add(-2 * time.Second)
+	limits.lastSweep = time.Now().Add(-rateLimitSweepInterval)
+	limits.allow("fresh")
+
+	tests := []struct {
+		key  string
+		kept bool
+	}{
+		{"stale", false},
+		{"fresh", true},
+	}
+	for _, tt := range tests {
+		if _, ok := limits.limiters[tt.key]; ok != tt.kept {
+			t.Errorf("limiter for %q kept = %v, want %v", tt.key, ok, tt.kept)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// RecoverHandler calls next and recovers from any panic it raises: the panic
+// is logged through l at ErrorLevel with the request method and path, the
+// panic value and the stack, and the client gets a 500 with a generic body
+// that reveals none of it. Unlike LoggingMiddleware, which re-raises panics,
+// it can be used as the outermost handler on its own.
+//
+// A panic with http.ErrAbortHandler is re-raised untouched, since it is how a
+// handler asks the server to abort the response.
+func RecoverHandler(l *zap.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(p)
+			}
+			withoutStacktrace(l).Error("http handler panicked",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Any("panic", p),
+				zap.ByteString("stacktrace", debug.Stack()),
+			)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
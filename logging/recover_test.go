@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestRecoverHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		// wantPanic is the logged panic value, or nil if nothing is logged.
+		wantPanic interface{}
+	}{
+		{
+			name:       "ok",
+			handler:    func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("hello")) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "panic",
+			handler:    func(http.ResponseWriter, *http.Request) { panic("boom") },
+			wantStatus: http.StatusInternalServerError,
+			wantPanic:  "boom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := logtest.NewTestLogger()
+			w := httptest.NewRecorder()
+			RecoverHandler(logger, tt.handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders?id=1", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantPanic == nil {
+				if logs.Len() != 0 {
+					t.Errorf("got %d entries, want none", logs.Len())
+				}
+				return
+			}
+			if body := w.Body.String(); strings.Contains(body, "goroutine") || strings.Contains(body, "boom") {
+				t.Errorf("body %q leaks the panic", body)
+			}
+			logtest.AssertLogged(t, logs, zapcore.ErrorLevel, "http handler panicked")
+			entry := logs.All()[0]
+			want := map[string]interface{}{
+				"method": http.MethodPost,
+				"path":   "/orders",
+				"panic":  tt.wantPanic,
+			}
+			for key, v := range want {
+				if got, _ := logtest.FieldValue(entry, key); got != v {
+					t.Errorf("%s = %v, want %v", key, got, v)
+				}
+			}
+			if stack, _ := logtest.FieldValue(entry, "stacktrace"); !strings.Contains(stack.(string), "goroutine") {
+				t.Errorf("stacktrace = %q, want the panicking goroutine's stack", stack)
+			}
+		})
+	}
+}
+
+func TestRecoverHandlerAbort(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	h := RecoverHandler(logger, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler re-raised", p)
+		}
+		if logs.Len() != 0 {
+			t.Errorf("got %d entries, want none for an aborted handler", logs.Len())
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
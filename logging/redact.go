@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// WithRedactedKeys replaces the value of every string, byte string or
+// reflected field whose key matches one of keys, ignoring case, with
+// "[REDACTED]" before it is encoded. Keys are also matched inside objects
+// logged with zap.Object and zap.Inline, at any depth.
+func WithRedactedKeys(keys ...string) Option {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return func(o *options) {
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &redactCore{Core: core, keys: set}
+		})
+	}
+}
+
+type redactCore struct {
+	zapcore.Core
+	keys map[string]struct{}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redact(fields)), keys: c.keys}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.redact(fields))
+}
+
+func (c *redactCore) match(key string) bool {
+	_, ok := c.keys[strings.ToLower(key)]
+	return ok
+}
+
+// redact returns fields with sensitive values replaced. The caller's slice is
+// only copied when something actually needs to change.
+func (c *redactCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		r, changed := c.redactField(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, r)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (c *redactCore) redactField(f zapcore.Field) (zapcore.Field, bool) {
+	switch f.Type {
+	case zapcore.StringType, zapcore.ByteStringType, zapcore.ReflectType:
+		if c.match(f.Key) {
+			return zap.String(f.Key, redacted), true
+		}
+	case zapcore.ObjectMarshalerType:
+		if m, ok := f.Interface.(zapcore.ObjectMarshaler); ok {
+			return zap.Object(f.Key, redactMarshaler{m, c}), true
+		}
+	case zapcore.InlineMarshalerType:
+		if m, ok := f.Interface.(zapcore.ObjectMarshaler); ok {
+			return zap.Inline(redactMarshaler{m, c}), true
+		}
+	}
+	return f, false
+}
+
+// redactMarshaler marshals the wrapped object through a redactEncoder so that
+// nested keys are redacted as well.
+type redactMarshaler struct {
+	zapcore.ObjectMarshaler
+	core *redactCore
+}
+
+func (m redactMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return m.ObjectMarshaler.MarshalLogObject(redactEncoder{enc, m.core})
+}
+
+type redactEncoder struct {
+	zapcore.ObjectEncoder
+	core *redactCore
+}
+
+func (e redactEncoder) AddString(key, value string) {
+	if e.core.match(key) {
+		value = redacted
+	}
+	e.ObjectEncoder.AddString(key, value)
+}
+
+func (e redactEncoder) AddByteString(key string, value []byte) {
+	if e.core.match(key) {
+		e.ObjectEncoder.AddString(key, redacted)
+		return
+	}
+	e.ObjectEncoder.AddByteString(key, value)
+}
+
+func (e redactEncoder) AddReflected(key string, value interface{}) error {
+	if e.core.match(key) {
+		e.ObjectEncoder.AddString(key, redacted)
+		return nil
+	}
+	return e.ObjectEncoder.AddReflected(key, value)
+}
+
+func (e redactEncoder) AddObject(key string, m zapcore.ObjectMarshaler) error {
+	return e.ObjectEncoder.AddObject(key, redactMarshaler{m, e.core})
+}
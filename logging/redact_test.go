@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type credentials struct {
+	user, password string
+	nested         *credentials
+}
+
+func (c credentials) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("user", c.user)
+	enc.AddString("Password", c.password)
+	if c.nested != nil {
+		return enc.AddObject("nested", c.nested)
+	}
+	return nil
+}
+
+func TestWithRedactedKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		field zap.Field
+		// want is the field's value in the JSON output.
+		want string
+	}{
+		{"string", zap.String("password", "hunter2"), `"[REDACTED]"`},
+		{"key case", zap.String("PASSWORD", "hunter2"), `"[REDACTED]"`},
+		{"byte string", zap.ByteString("token", []byte("hunter2")), `"[REDACTED]"`},
+		{"reflected", zap.Any("token", map[string]string{"value": "hunter2"}), `"[REDACTED]"`},
+		{"other key", zap.String("user", "marmotedu"), `"marmotedu"`},
+		{
+			"object",
+			zap.Object("credentials", credentials{user: "marmotedu", password: "hunter2"}),
+			`{"user":"marmotedu","Password":"[REDACTED]"}`,
+		},
+		{
+			"nested object",
+			zap.Object("credentials", credentials{user: "a", nested: &credentials{user: "b", password: "hunter2"}}),
+			`{"user":"a","Password":"[REDACTED]","nested":{"user":"b","Password":"[REDACTED]"}}`,
+		},
+		{
+			"inline object",
+			zap.Inline(credentials{user: "marmotedu", password: "hunter2"}),
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithRedactedKeys("password", "token"))
+			logger.Info("login", tt.field)
+
+			entry := entries()[0]
+			out, err := json.Marshal(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(out), "hunter2") {
+				t.Errorf("output %s contains the secret", out)
+			}
+			if tt.want == "" {
+				if entry["Password"] != redacted {
+					t.Errorf("Password = %v, want %q", entry["Password"], redacted)
+				}
+				return
+			}
+			got, err := json.Marshal(entry[tt.field.Key])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !jsonEqual(t, string(got), tt.want) {
+				t.Errorf("%s = %s, want %s", tt.field.Key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRedactedKeysWith(t *testing.T) {
+	logger, entries := newFileLogger(t, WithRedactedKeys("password"))
+	logger.With(zap.String("password", "hunter2")).Info("login")
+
+	if got := entries()[0]["password"]; got != redacted {
+		t.Errorf("password = %v, want %q", got, redacted)
+	}
+}
+
+// jsonEqual reports whether the JSON documents a and b hold the same value.
+func jsonEqual(t *testing.T, a, b string) bool {
+	t.Helper()
+	var va, vb interface{}
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		t.Fatalf("%s: %v", a, err)
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		t.Fatalf("%s: %v", b, err)
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return string(ja) == string(jb)
+}
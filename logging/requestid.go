@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware gives every request an ID: the one in its X-Request-ID
+// header, kept verbatim, or else a newly generated random UUID. The ID is
+// echoed in the X-Request-ID response header, and a child of l carrying it
+// as a "request_id" field is stored in the request context, so handlers that
+// log through LoggerFromContext tag their entries with it.
+//
+// Put it in front of LoggingMiddleware and the other middlewares, so that
+// they see the ID as well.
+func RequestIDMiddleware(l *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestIDHeader)
+			if id == "" {
+				id = newUUID()
+			}
+			w.Header().Set(requestIDHeader, id)
+			ctx := ContextWithLogger(r.Context(), l.With(zap.String("request_id", id)))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// newUUID returns a random, version 4 UUID.
+func newUUID() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+
+	var s [36]byte
+	hex.Encode(s[0:8], u[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], u[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], u[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], u[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], u[10:])
+	return string(s[:])
+}
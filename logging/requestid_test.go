@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+var uuidV4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name string
+		// header is the incoming X-Request-ID, if any.
+		header string
+		// want matches the ID the request is given.
+		want *regexp.Regexp
+	}{
+		{"provided", "abc-123", regexp.MustCompile(`^abc-123$`)},
+		{"provided verbatim", " Trace/42 ", regexp.MustCompile(`^ Trace/42 $`)},
+		{"generated", "", uuidV4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := logtest.NewTestLogger()
+			h := RequestIDMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				LoggerFromContext(r.Context()).Info("handled")
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Request-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			id := w.Header().Get("X-Request-ID")
+			if !tt.want.MatchString(id) {
+				t.Errorf("X-Request-ID = %q, want it to match %s", id, tt.want)
+			}
+			if logs.Len() != 1 {
+				t.Fatalf("got %d entries, want the handler's", logs.Len())
+			}
+			if got, _ := logtest.FieldValue(logs.All()[0], "request_id"); got != id {
+				t.Errorf("request_id = %v, want %q", got, id)
+			}
+		})
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := newUUID()
+		if !uuidV4.MatchString(id) {
+			t.Fatalf("newUUID = %q, want a version 4 UUID", id)
+		}
+		if seen[id] {
+			t.Fatalf("newUUID returned %q twice", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RingBufferCore returns a core that keeps the JSON encoding of the last
+// capacity entries in memory, evicting the oldest ones once it is full, and a
+// handler that serves them as a JSON array, oldest first and newest last.
+// A capacity below 1 is raised to 1.
+//
+// The core accepts all levels; combine it with the regular core using
+// zapcore.NewTee and mount the handler on a debug endpoint:
+//
+//	ring, handler := logging.RingBufferCore(100)
+//	logger = logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+//		return zapcore.NewTee(c, ring)
+//	}))
+//	http.Handle("/debug/logs", handler)
+func RingBufferCore(capacity int) (zapcore.Core, http.Handler) {
+	if capacity < 1 {
+		capacity = 1
+	}
+	ring := &ringBuffer{entries: make([][]byte, capacity)}
+	core := &ringCore{
+		LevelEnabler: zapcore.DebugLevel,
+		enc:          zapcore.NewJSONEncoder(newOptions().config.EncoderConfig),
+		ring:         ring,
+	}
+	return core, ring
+}
+
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+func (r *ringBuffer) add(entry []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the stored entries, oldest first.
+func (r *ringBuffer) snapshot() []json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []json.RawMessage
+	if r.full {
+		for _, e := range r.entries[r.next:] {
+			out = append(out, e)
+		}
+	}
+	for _, e := range r.entries[:r.next] {
+		out = append(out, e)
+	}
+	return out
+}
+
+func (r *ringBuffer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	entries := r.snapshot()
+	if entries == nil {
+		entries = []json.RawMessage{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+type ringCore struct {
+	zapcore.LevelEnabler
+	enc  zapcore.Encoder
+	ring *ringBuffer
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &ringCore{LevelEnabler: c.LevelEnabler, enc: enc, ring: c.ring}
+}
+
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	// The buffer goes back to zap's pool, so keep a copy of its contents.
+	c.ring.add(bytes.TrimRight(append([]byte(nil), buf.Bytes()...), "\n"))
+	buf.Free()
+	return nil
+}
+
+func (c *ringCore) Sync() error {
+	return nil
+}
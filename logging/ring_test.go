@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// ringEntries fetches the entries served by handler.
+func ringEntries(t *testing.T, handler http.Handler) []map[string]interface{} {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/logs", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("%s: %v", w.Body, err)
+	}
+	return entries
+}
+
+func TestRingBufferCore(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		logged   int
+		// wantFirst is the index of the oldest entry kept.
+		wantFirst, wantLen int
+	}{
+		{"empty", 100, 0, 0, 0},
+		{"not full", 100, 50, 0, 50},
+		{"exactly full", 100, 100, 0, 100},
+		{"evicting", 100, 150, 50, 100},
+		{"zero capacity", 0, 3, 2, 1},
+		{"negative capacity", -5, 3, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, handler := RingBufferCore(tt.capacity)
+			logger := zap.New(core)
+			for i := 0; i < tt.logged; i++ {
+				logger.Debug(fmt.Sprintf("entry %d", i))
+			}
+
+			entries := ringEntries(t, handler)
+			if entries == nil {
+				t.Fatal("handler served null instead of an array")
+			}
+			if len(entries) != tt.wantLen {
+				t.Fatalf("got %d entries, want %d", len(entries), tt.wantLen)
+			}
+			for i, entry := range entries {
+				if want := fmt.Sprintf("entry %d", tt.wantFirst+i); entry["msg"] != want {
+					t.Errorf("entry %d msg = %v, want %q", i, entry["msg"], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRingBufferCoreWith(t *testing.T) {
+	core, handler := RingBufferCore(10)
+	zap.New(core).With(zap.String("request_id", "abc")).Info("handled")
+
+	if got := ringEntries(t, handler)[0]["request_id"]; got != "abc" {
+		t.Errorf("request_id = %v, want abc", got)
+	}
+}
+
+func TestRingBufferCoreConcurrent(t *testing.T) {
+	core, handler := RingBufferCore(100)
+	logger := zap.New(core)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Info("concurrent")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				ringEntries(t, handler)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(ringEntries(t, handler)); n != 100 {
+		t.Errorf("got %d entries, want 100", n)
+	}
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// WithRotatingFile sends the logger's output to path instead of standard
+// error, rotating the file once it grows past maxSizeMB megabytes.
+// At most maxBackups rotated files older than maxAgeDays days are kept, and
+// rotated files are gzipped when compress is set; WithRotationCompression
+// chooses other codecs. Zero values fall back to lumberjack's defaults.
+//
+// The directory holding path is created with 0755 if it does not exist yet.
+func WithRotatingFile(path string, maxSizeMB, maxBackups, maxAgeDays int, compress bool) Option {
+	return func(o *options) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			o.setErr(fmt.Errorf("logging: create log directory: %w", err))
+			return
+		}
+		f := &rotatingFile{Logger: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxBackups,
+			MaxAge:     maxAgeDays,
+			Compress:   compress,
+		}}
+		o.rotatingFiles = append(o.rotatingFiles, f)
+		o.sinks = append(o.sinks, f)
+	}
+}
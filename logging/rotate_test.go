@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fillRotatingFile logs a little more than a megabyte to logger, in entries
+// with distinct messages so that none is sampled away.
+func fillRotatingFile(logger *zap.Logger) {
+	payload := strings.Repeat("x", 10*1024)
+	for i := 0; i < 110; i++ {
+		logger.Info(fmt.Sprintf("filler %d", i), zap.String("payload", payload))
+	}
+}
+
+// backups returns the names of the files in dir other than current.
+func backups(t *testing.T, dir, current string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Name() != current {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func TestWithRotatingFile(t *testing.T) {
+	tests := []struct {
+		name string
+		// dir is the log directory, relative to a temporary directory.
+		dir string
+	}{
+		{"existing directory", "."},
+		{"missing directory", filepath.Join("a", "b")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), tt.dir)
+			path := filepath.Join(dir, "app.log")
+			logger, err := NewLogger(WithRotatingFile(path, 1, 3, 0, false))
+			if err != nil {
+				t.Fatalf("NewLogger: %v", err)
+			}
+
+			fillRotatingFile(logger)
+
+			names := backups(t, dir, "app.log")
+			if len(names) != 1 {
+				t.Fatalf("got backups %v, want one", names)
+			}
+			if !strings.HasPrefix(names[0], "app-") || !strings.HasSuffix(names[0], ".log") {
+				t.Errorf("backup %q is not named like app-<time>.log", names[0])
+			}
+			if entries := readEntries(t, path); len(entries) == 0 {
+				t.Error("nothing was logged to the current file after rotating")
+			}
+		})
+	}
+}
+
+func TestWithRotatingFileUnwritableDirectory(t *testing.T) {
+	parent := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(parent, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLogger(WithRotatingFile(filepath.Join(parent, "app.log"), 1, 3, 0, false)); err == nil {
+		t.Error("NewLogger succeeded with a log directory below a regular file")
+	}
+}
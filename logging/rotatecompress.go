@@ -0,0 +1,232 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	zstdSuffix = ".zst"
+	// backupTimeLayout is the timestamp lumberjack puts in backup names.
+	backupTimeLayout = "2006-01-02T15-04-05.000"
+	// lumberjackDefaultMaxSize is the MaxSize lumberjack uses for zero, in
+	// megabytes.
+	lumberjackDefaultMaxSize = 100
+)
+
+// WithRotationCompression compresses the backups made by WithRotatingFile
+// with codec, "gzip" or "zstd", overriding its compress argument. Backups
+// are compressed by a background goroutine, so rotation does not wait for
+// it, and each is removed only once its compressed copy has been written
+// completely. Zstd backups end in ".zst" and are subject to the same
+// maxBackups and maxAgeDays limits as the others.
+//
+// Build fails for any other codec.
+func WithRotationCompression(codec string) Option {
+	return func(o *options) {
+		switch codec {
+		case "gzip", "zstd":
+			o.rotationCodec = codec
+		default:
+			o.setErr(fmt.Errorf("logging: unknown rotation compression %q, valid codecs are gzip and zstd", codec))
+		}
+	}
+}
+
+// rotatingFile is the sink WithRotatingFile adds.
+type rotatingFile struct {
+	*lumberjack.Logger
+
+	// zstd is set when backups are compressed with zstd, the codec
+	// lumberjack has no support for.
+	zstd *zstdCompressor
+
+	mu sync.Mutex
+	// size estimates the current file's size to tell when lumberjack has
+	// rotated it.
+	size int64
+}
+
+// setCodec switches the compression of f's backups to codec.
+func (f *rotatingFile) setCodec(codec string) {
+	switch codec {
+	case "gzip":
+		f.Compress = true
+	case "zstd":
+		f.Compress = false
+		f.zstd = &zstdCompressor{file: f.Logger, trigger: make(chan struct{}, 1)}
+		if info, err := os.Stat(f.Filename); err == nil {
+			f.size = info.Size()
+		}
+		// Compress what earlier runs left behind.
+		f.zstd.compressSoon()
+	}
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	if f.zstd == nil {
+		return f.Logger.Write(p)
+	}
+	maxSize := int64(f.MaxSize)
+	if maxSize == 0 {
+		maxSize = lumberjackDefaultMaxSize
+	}
+	maxSize *= 1024 * 1024
+
+	// lumberjack rotates before a write that would take the file past its
+	// maximum size.
+	f.mu.Lock()
+	rotated := f.size+int64(len(p)) > maxSize
+	if rotated {
+		f.size = 0
+	}
+	f.size += int64(len(p))
+	f.mu.Unlock()
+
+	n, err := f.Logger.Write(p)
+	if rotated {
+		f.zstd.compressSoon()
+	}
+	return n, err
+}
+
+// Sync does nothing: lumberjack writes straight to the file.
+func (f *rotatingFile) Sync() error {
+	return nil
+}
+
+// zstdCompressor compresses the backups of a lumberjack file in the
+// background.
+type zstdCompressor struct {
+	file    *lumberjack.Logger
+	start   sync.Once
+	trigger chan struct{}
+}
+
+// compressSoon has the background goroutine, started on first use, look for
+// backups to compress.
+func (c *zstdCompressor) compressSoon() {
+	c.start.Do(func() {
+		go func() {
+			for range c.trigger {
+				c.run()
+			}
+		}()
+	})
+	select {
+	case c.trigger <- struct{}{}:
+	default: // a run is already pending and will see the new backup
+	}
+}
+
+func (c *zstdCompressor) run() {
+	dir := filepath.Dir(c.file.Filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		path string
+		time time.Time
+	}
+	var compressed []backup
+	for _, e := range entries {
+		name := e.Name()
+		if t, ok := c.backupTime(strings.TrimSuffix(name, zstdSuffix)); ok {
+			path := filepath.Join(dir, name)
+			if !strings.HasSuffix(name, zstdSuffix) {
+				if compressZstd(path) != nil {
+					continue
+				}
+				path += zstdSuffix
+			}
+			compressed = append(compressed, backup{path: path, time: t})
+		}
+	}
+
+	// lumberjack only counts uncompressed and gzipped backups, so the zstd
+	// ones are pruned here.
+	sort.Slice(compressed, func(i, j int) bool { return compressed[i].time.After(compressed[j].time) })
+	cutoff := time.Time{}
+	if c.file.MaxAge > 0 {
+		cutoff = time.Now().Add(-time.Duration(c.file.MaxAge) * 24 * time.Hour)
+	}
+	for i, b := range compressed {
+		if (c.file.MaxBackups > 0 && i >= c.file.MaxBackups) || b.time.Before(cutoff) {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// backupTime parses the timestamp lumberjack gives the backup called name,
+// reporting false if name is not a backup of the file.
+func (c *zstdCompressor) backupTime(name string) (time.Time, bool) {
+	base := filepath.Base(c.file.Filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+		return time.Time{}, false
+	}
+	loc := time.UTC
+	if c.file.LocalTime {
+		loc = time.Local
+	}
+	t, err := time.ParseInLocation(backupTimeLayout, name[len(prefix):len(name)-len(ext)], loc)
+	return t, err == nil
+}
+
+// compressZstd replaces the file at path with path+".zst". The original is
+// removed only once the compressed copy is complete.
+func compressZstd(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp := path + zstdSuffix + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	enc, err := zstd.NewWriter(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(enc, src); err != nil {
+		enc.Close()
+		return err
+	}
+	if err = enc.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path+zstdSuffix); err != nil {
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
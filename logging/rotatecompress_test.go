@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// waitForBackups polls dir until the only backup of app.log ends in suffix,
+// as the background compression leaves it, and returns its path.
+func waitForBackups(t *testing.T, dir, suffix string) string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		names := backups(t, dir, "app.log")
+		if len(names) == 1 && strings.HasSuffix(names[0], suffix) {
+			return filepath.Join(dir, names[0])
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got backups %v, want one ending in %s", names, suffix)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWithRotationCompression(t *testing.T) {
+	tests := []struct {
+		codec  string
+		suffix string
+		open   func(io.Reader) (io.Reader, error)
+	}{
+		{"gzip", ".log.gz", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"zstd", ".log.zst", func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.codec, func(t *testing.T) {
+			dir := t.TempDir()
+			logger, err := NewLogger(
+				WithRotatingFile(filepath.Join(dir, "app.log"), 1, 3, 0, false),
+				WithRotationCompression(tt.codec),
+			)
+			if err != nil {
+				t.Fatalf("NewLogger: %v", err)
+			}
+
+			fillRotatingFile(logger)
+
+			f, err := os.Open(waitForBackups(t, dir, tt.suffix))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			r, err := tt.open(f)
+			if err != nil {
+				t.Fatalf("opening the backup: %v", err)
+			}
+			b, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("decompressing the backup: %v", err)
+			}
+			if msg := decodeLine(t, string(bytes.SplitN(b, []byte("\n"), 2)[0]))["msg"]; msg != "filler 0" {
+				t.Errorf("backup starts with msg %v, want filler 0", msg)
+			}
+		})
+	}
+}
+
+func TestWithRotationCompressionUnknownCodec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if _, err := NewLogger(WithRotatingFile(path, 1, 3, 0, false), WithRotationCompression("lz4")); err == nil {
+		t.Error("NewLogger succeeded with an unknown codec")
+	}
+}
+
+func TestZstdCompressorPrunes(t *testing.T) {
+	dir := t.TempDir()
+	c := &zstdCompressor{file: &lumberjack.Logger{Filename: filepath.Join(dir, "app.log"), MaxBackups: 2}}
+	names := []string{
+		"app-2023-10-24T11-06-18.000.log.zst",
+		"app-2023-10-24T11-06-19.000.log",
+		"app-2023-10-24T11-06-20.000.log",
+		"other-2023-10-24T11-06-20.000.log",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.run()
+
+	got := backups(t, dir, "app.log")
+	want := []string{
+		"app-2023-10-24T11-06-19.000.log.zst",
+		"app-2023-10-24T11-06-20.000.log.zst",
+		"other-2023-10-24T11-06-20.000.log",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("directory holds %v, want %v", got, want)
+	}
+}
+
+func TestCompressZstd(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		setup   func(path string)
+		wantErr bool
+		// wantBackup reports whether the uncompressed backup is left.
+		wantBackup bool
+	}{
+		{
+			name:  "backup",
+			setup: func(path string) { os.WriteFile(path, []byte(`{"msg":"kept"}`+"\n"), 0o644) },
+		},
+		{
+			name:    "missing",
+			setup:   func(string) {},
+			wantErr: true,
+		},
+		{
+			name: "unwritable destination",
+			setup: func(path string) {
+				os.WriteFile(path, []byte(`{"msg":"kept"}`+"\n"), 0o644)
+				// A directory in the way of the temporary file.
+				os.Mkdir(path+zstdSuffix+".tmp", 0o755)
+			},
+			wantErr:    true,
+			wantBackup: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".log")
+			tt.setup(path)
+
+			err := compressZstd(path)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compressZstd = %v, want error: %v", err, tt.wantErr)
+			}
+			if _, err := os.Stat(path); (err == nil) != tt.wantBackup {
+				t.Errorf("backup left = %v, want %v", err == nil, tt.wantBackup)
+			}
+			if _, err := os.Stat(path + zstdSuffix); (err == nil) == tt.wantErr {
+				t.Errorf("compressed copy written = %v, want %v", err == nil, !tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// routingDefaultFile takes the entries without the route field.
+const routingDefaultFile = "default"
+
+// NewRoutingCore returns a core that writes each entry, JSON-encoded like
+// NewLogger's at InfoLevel and above, to dir/<value>.log, where value is the
+// entry's routeField field, such as a tenant ID. The field may be passed to
+// the log call or attached earlier with With. Entries without it, or with an
+// empty value, go to dir/default.log.
+//
+// Files are created in append mode, along with dir, the first time an entry
+// is routed to them, and then kept open and reused for every later entry;
+// Sync syncs all of them. Characters in a value that are not safe in a file
+// name, path separators included, are replaced by "_", so that a value
+// cannot address a file outside dir.
+func NewRoutingCore(dir string, routeField string) zapcore.Core {
+	return &routingCore{
+		LevelEnabler: zapcore.InfoLevel,
+		enc:          zapcore.NewJSONEncoder(newOptions().config.EncoderConfig),
+		field:        routeField,
+		files:        &routingFiles{dir: dir, open: make(map[string]*os.File)},
+	}
+}
+
+// routingFiles caches the files a routing core and its clones write to.
+type routingFiles struct {
+	dir string
+
+	mu   sync.Mutex
+	open map[string]*os.File
+}
+
+// get returns the file for route, opening it on first use.
+func (f *routingFiles) get(route string) (*os.File, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if file, ok := f.open[route]; ok {
+		return file, nil
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(f.dir, route+".log"), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	f.open[route] = file
+	return file, nil
+}
+
+func (f *routingFiles) sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var errs error
+	for _, file := range f.open {
+		errs = multierr.Append(errs, file.Sync())
+	}
+	return errs
+}
+
+type routingCore struct {
+	zapcore.LevelEnabler
+	enc   zapcore.Encoder
+	field string
+	files *routingFiles
+	// route is the value of field when it was attached with With.
+	route    string
+	hasRoute bool
+}
+
+func (c *routingCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	if route, ok := fieldValueString(fields, c.field); ok {
+		clone.route, clone.hasRoute = route, true
+	}
+	return &clone
+}
+
+func (c *routingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *routingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	route, ok := fieldValueString(fields, c.field)
+	if !ok {
+		route, ok = c.route, c.hasRoute
+	}
+	if !ok || route == "" {
+		route = routingDefaultFile
+	}
+
+	file, err := c.files.get(routeFileName(route))
+	if err != nil {
+		return err
+	}
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(buf.Bytes())
+	buf.Free()
+	if err != nil {
+		return err
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		// Like zapcore's own core, sync before a Panic or Fatal entry ends
+		// the program.
+		return c.Sync()
+	}
+	return nil
+}
+
+func (c *routingCore) Sync() error {
+	return c.files.sync()
+}
+
+// routeFileName makes route usable as a file name within the routing
+// directory.
+func routeFileName(route string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, route)
+	if name == "." || name == ".." {
+		return strings.Repeat("_", len(name))
+	}
+	return name
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// closeRoutes closes the files core has open once the test is done, so that
+// its temporary directory can be removed.
+func closeRoutes(t *testing.T, core *routingCore) {
+	t.Cleanup(func() {
+		for _, f := range core.files.open {
+			f.Close()
+		}
+	})
+}
+
+func TestNewRoutingCore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tenants")
+	core := NewRoutingCore(dir, "tenant")
+	closeRoutes(t, core.(*routingCore))
+	logger := zap.New(core)
+	globex := logger.With(zap.String("tenant", "globex"))
+
+	logger.Info("acme 1", zap.String("tenant", "acme"))
+	globex.Info("globex 1")
+	logger.Info("acme 2", zap.String("tenant", "acme"))
+	// A field passed to the call wins over the one attached with With.
+	globex.Info("acme 3", zap.String("tenant", "acme"))
+	logger.Info("untagged")
+	logger.Info("empty", zap.String("tenant", ""))
+	logger.Debug("below the level", zap.String("tenant", "acme"))
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"acme.log", []string{"acme 1", "acme 2", "acme 3"}},
+		{"globex.log", []string{"globex 1"}},
+		{"default.log", []string{"untagged", "empty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			var got []string
+			for _, e := range readEntries(t, filepath.Join(dir, tt.file)) {
+				got = append(got, e["msg"].(string))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s holds %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+
+	files, _ := os.ReadDir(dir)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+	if want := []string{"acme.log", "default.log", "globex.log"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("directory holds %v, want %v", names, want)
+	}
+	if open := len(core.(*routingCore).files.open); open != 3 {
+		t.Errorf("core has %d files open, want one per route", open)
+	}
+}
+
+func TestRoutingCoreReusesFiles(t *testing.T) {
+	core := NewRoutingCore(t.TempDir(), "tenant").(*routingCore)
+	closeRoutes(t, core)
+	logger := zap.New(core)
+	logger.Info("first", zap.String("tenant", "acme"))
+	first := core.files.open["acme"]
+	logger.With(zap.String("tenant", "acme")).Info("second")
+
+	if core.files.open["acme"] != first {
+		t.Error("the file was reopened for the second entry")
+	}
+}
+
+func TestRouteFileName(t *testing.T) {
+	tests := []struct {
+		route string
+		want  string
+	}{
+		{"acme", "acme"},
+		{"acme-eu_1.prod", "acme-eu_1.prod"},
+		{"../etc/passwd", ".._etc_passwd"},
+		{"a/b", "a_b"},
+		{".", "_"},
+		{"..", "__"},
+		{"ünïcode", "_n_code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			if got := routeFileName(tt.route); got != tt.want {
+				t.Errorf("routeFileName(%q) = %q, want %q", tt.route, got, tt.want)
+			}
+		})
+	}
+}
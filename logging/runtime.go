@@ -0,0 +1,24 @@
+package logging
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// WithRuntimeFields adds the machine's hostname as "host", the process ID as
+// "pid" and serviceVersion as "version" to every entry, sugared or not.
+// The host is "unknown" if the hostname cannot be looked up.
+func WithRuntimeFields(serviceVersion string) Option {
+	return func(o *options) {
+		host, err := os.Hostname()
+		if err != nil {
+			host = "unknown"
+		}
+		o.zapOptions = append(o.zapOptions, zap.Fields(
+			zap.String("host", host),
+			zap.Int("pid", os.Getpid()),
+			zap.String("version", serviceVersion),
+		))
+	}
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"os"
+	"testing"
+)
+
+func TestWithRuntimeFields(t *testing.T) {
+	logger, entries := newFileLogger(t, WithRuntimeFields("v1.2.3"))
+	logger.Info("plain")
+	logger.Sugar().Infow("sugared", "attempt", 3)
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got))
+	}
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{"host", host},
+		{"pid", float64(os.Getpid())},
+		{"version", "v1.2.3"},
+	}
+	for _, entry := range got {
+		for _, tt := range tests {
+			if entry[tt.key] != tt.want {
+				t.Errorf("%v: %s = %v, want %v", entry["msg"], tt.key, entry[tt.key], tt.want)
+			}
+		}
+	}
+}
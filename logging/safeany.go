@@ -0,0 +1,268 @@
+package logging
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultSafeAnyMaxDepth is how deep SafeAny follows nested values.
+	defaultSafeAnyMaxDepth = 32
+
+	cycleMarker    = "<cycle>"
+	maxDepthMarker = "<max depth>"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SafeAny is zap.Any for values that may refer to themselves. zap.Any
+// encodes structs, maps and slices with encoding/json, which on a value
+// containing a pointer cycle, such as a node pointing back at its parent,
+// recurses until it gives up with an error a thousand levels down, if the
+// stack lasts that long.
+//
+// SafeAny first walks such values with reflection. If they have no cycle
+// and are at most 32 levels deep, it returns exactly what zap.Any does.
+// Otherwise it logs a copy in which every reference back to a value being
+// walked is replaced by "<cycle>", and everything nested deeper than the
+// limit by "<max depth>". The copy follows encoding/json's rules for field
+// names, json tags, embedded structs and map keys, and leaves values with a
+// MarshalJSON or MarshalText method to it. Values zap.Any does not reflect,
+// like strings or ObjectMarshalers, are passed through untouched.
+//
+// Use SafeAnyDepth to choose another limit.
+func SafeAny(key string, val interface{}) zap.Field {
+	return SafeAnyDepth(key, val, defaultSafeAnyMaxDepth)
+}
+
+// SafeAnyDepth is SafeAny with values nested more than maxDepth structs,
+// maps, slices or arrays deep cut off.
+func SafeAnyDepth(key string, val interface{}, maxDepth int) zap.Field {
+	f := zap.Any(key, val)
+	if f.Type != zapcore.ReflectType {
+		return f
+	}
+	check := &safeWalker{maxDepth: maxDepth}
+	check.walk(reflect.ValueOf(val), 0)
+	if !check.elided {
+		return f
+	}
+	elide := &safeWalker{maxDepth: maxDepth, build: true}
+	return zap.Reflect(key, elide.walk(reflect.ValueOf(val), 0))
+}
+
+// safeWalker walks a value the way encoding/json would encode it. With build
+// set, walk returns a copy of the value with cycles and overly deep values
+// replaced by markers; without, it only reports whether there are any.
+type safeWalker struct {
+	maxDepth int
+	build    bool
+	// visiting holds the references on the path from the root to the value
+	// being walked.
+	visiting map[safeVisit]struct{}
+	elided   bool
+}
+
+// safeVisit identifies a reference. Slices sharing an array differ by length.
+type safeVisit struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+func (w *safeWalker) walk(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return w.keep(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return w.walk(v.Elem(), depth)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return w.enter(v, 0, func() interface{} { return w.walk(v.Elem(), depth) })
+	case reflect.Struct:
+		return w.nest(depth, func() interface{} { return w.walkStruct(v, depth+1) })
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		return w.enter(v, 0, func() interface{} {
+			return w.nest(depth, func() interface{} { return w.walkMap(v, depth+1) })
+		})
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Base64-encoded by encoding/json, not walked.
+			return w.keep(v)
+		}
+		return w.enter(v, v.Len(), func() interface{} {
+			return w.nest(depth, func() interface{} { return w.walkElems(v, depth+1) })
+		})
+	case reflect.Array:
+		return w.nest(depth, func() interface{} { return w.walkElems(v, depth+1) })
+	default:
+		return w.keep(v)
+	}
+}
+
+func (w *safeWalker) keep(v reflect.Value) interface{} {
+	if !w.build {
+		return nil
+	}
+	return v.Interface()
+}
+
+// enter walks the value v refers to with the help of walk, unless v is
+// already being walked further up.
+func (w *safeWalker) enter(v reflect.Value, n int, walk func() interface{}) interface{} {
+	visit := safeVisit{typ: v.Type(), ptr: v.Pointer(), len: n}
+	if _, ok := w.visiting[visit]; ok {
+		w.elided = true
+		return cycleMarker
+	}
+	if w.visiting == nil {
+		w.visiting = make(map[safeVisit]struct{})
+	}
+	w.visiting[visit] = struct{}{}
+	defer delete(w.visiting, visit)
+	return walk()
+}
+
+// nest runs walk for a value nested depth levels deep, unless that is too
+// deep.
+func (w *safeWalker) nest(depth int, walk func() interface{}) interface{} {
+	if depth >= w.maxDepth {
+		w.elided = true
+		return maxDepthMarker
+	}
+	return walk()
+}
+
+func (w *safeWalker) walkStruct(v reflect.Value, depth int) interface{} {
+	var out map[string]interface{}
+	if w.build {
+		out = make(map[string]interface{}, v.NumField())
+	}
+	w.addFields(out, v, depth)
+	return out
+}
+
+// addFields adds the fields of struct v to out, inlining embedded structs.
+func (w *safeWalker) addFields(out map[string]interface{}, v reflect.Value, depth int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if sf.Anonymous && name == "" {
+			et := sf.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				w.addFields(out, fv, depth)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyJSONValue(fv) {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		elem := w.walk(fv, depth)
+		if w.build {
+			out[name] = elem
+		}
+	}
+}
+
+func (w *safeWalker) walkMap(v reflect.Value, depth int) interface{} {
+	var out map[string]interface{}
+	if w.build {
+		out = make(map[string]interface{}, v.Len())
+	}
+	iter := v.MapRange()
+	for iter.Next() {
+		elem := w.walk(iter.Value(), depth)
+		if w.build {
+			out[mapKeyString(iter.Key())] = elem
+		}
+	}
+	return out
+}
+
+func (w *safeWalker) walkElems(v reflect.Value, depth int) interface{} {
+	var out []interface{}
+	if w.build {
+		out = make([]interface{}, v.Len())
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem := w.walk(v.Index(i), depth)
+		if w.build {
+			out[i] = elem
+		}
+	}
+	return out
+}
+
+// mapKeyString renders a map key the way encoding/json does.
+func mapKeyString(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if b, err := tm.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(k.Interface())
+}
+
+// isEmptyJSONValue reports whether omitempty leaves v out.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
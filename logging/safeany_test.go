@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type safeNode struct {
+	Name     string      `json:"name"`
+	Parent   *safeNode   `json:"parent,omitempty"`
+	Children []*safeNode `json:"children,omitempty"`
+	internal int
+}
+
+type safeList struct {
+	V    int
+	Next *safeList
+}
+
+func TestSafeAny(t *testing.T) {
+	self := &safeList{V: 1}
+	self.Next = self
+
+	root := &safeNode{Name: "root", internal: 1}
+	child := &safeNode{Name: "child", Parent: root}
+	root.Children = []*safeNode{child}
+
+	loop := map[string]interface{}{"id": "m"}
+	loop["self"] = loop
+
+	type embedded struct{ ID int }
+	leaf := &safeList{V: 9}
+	tests := []struct {
+		name string
+		val  interface{}
+		want interface{}
+	}{
+		{
+			name: "self reference",
+			val:  self,
+			want: map[string]interface{}{"V": 1.0, "Next": cycleMarker},
+		},
+		{
+			name: "parent and children",
+			val:  root,
+			want: map[string]interface{}{
+				"name": "root",
+				"children": []interface{}{
+					map[string]interface{}{"name": "child", "parent": cycleMarker},
+				},
+			},
+		},
+		{
+			name: "map",
+			val:  loop,
+			want: map[string]interface{}{"id": "m", "self": cycleMarker},
+		},
+		{
+			name: "shared, not cyclic",
+			val:  []*safeList{leaf, leaf},
+			want: []interface{}{
+				map[string]interface{}{"V": 9.0, "Next": nil},
+				map[string]interface{}{"V": 9.0, "Next": nil},
+			},
+		},
+		{
+			name: "embedded",
+			val: struct {
+				embedded
+				Self *safeList
+			}{embedded{7}, self},
+			want: map[string]interface{}{
+				"ID":   7.0,
+				"Self": map[string]interface{}{"V": 1.0, "Next": cycleMarker},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			logger.Info("walked", SafeAny("value", tt.val))
+
+			if got := entries()[0]["value"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeAnyDepth(t *testing.T) {
+	list := &safeList{V: 1, Next: &safeList{V: 2, Next: &safeList{V: 3}}}
+	tests := []struct {
+		maxDepth int
+		want     interface{}
+	}{
+		{1, map[string]interface{}{"V": 1.0, "Next": maxDepthMarker}},
+		{2, map[string]interface{}{"V": 1.0, "Next": map[string]interface{}{"V": 2.0, "Next": maxDepthMarker}}},
+		{3, map[string]interface{}{"V": 1.0, "Next": map[string]interface{}{
+			"V": 2.0, "Next": map[string]interface{}{"V": 3.0, "Next": nil},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.maxDepth), func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			logger.Info("walked", SafeAnyDepth("value", list, tt.maxDepth))
+
+			if got := entries()[0]["value"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeAnyAcyclic(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+	}{
+		{"string", "marmotedu"},
+		{"int", 3},
+		{"struct", safeList{V: 1, Next: &safeList{V: 2}}},
+		{"map", map[string]int{"a": 1}},
+		{"slice", []string{"a", "b"}},
+		{"nil", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := SafeAny("k", tt.val), zap.Any("k", tt.val); !got.Equals(want) {
+				t.Errorf("SafeAny = %+v, want zap.Any's %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithSampling caps repetitive logging: per second, the first initial entries
+// with the same level and message are logged, and after that only every
+// thereafter-th one. Entries are keyed on level and message only, as zap's
+// sampler does, so differing fields do not make entries distinct.
+//
+// NewLogger samples with initial and thereafter of 100 by default, like zap's
+// production config. Development configs are not sampled. A non-positive
+// initial turns sampling off.
+func WithSampling(initial, thereafter int) Option {
+	return func(o *options) {
+		o.samplingExcept = nil
+		if initial <= 0 {
+			o.config.Sampling = nil
+			return
+		}
+		o.config.Sampling = &zap.SamplingConfig{
+			Initial:    initial,
+			Thereafter: thereafter,
+		}
+	}
+}
+
+// WithSamplingExcept is WithSampling for entries below minLevel only: entries
+// at minLevel and above are never sampled, so a flood of errors is logged in
+// full while the same flood at Info is capped. WithSampling given after it
+// samples every level again.
+func WithSamplingExcept(minLevel zapcore.Level, initial, thereafter int) Option {
+	return func(o *options) {
+		WithSampling(initial, thereafter)(o)
+		o.samplingExcept = &minLevel
+	}
+}
+
+// unsampledCore sends entries at min and above to raw, bypassing the sampler
+// that sampled wraps around it.
+type unsampledCore struct {
+	sampled, raw zapcore.Core
+	min          zapcore.Level
+}
+
+func (c *unsampledCore) Enabled(level zapcore.Level) bool {
+	return c.raw.Enabled(level)
+}
+
+func (c *unsampledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &unsampledCore{sampled: c.sampled.With(fields), raw: c.raw.With(fields), min: c.min}
+}
+
+func (c *unsampledCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= c.min {
+		return c.raw.Check(ent, ce)
+	}
+	return c.sampled.Check(ent, ce)
+}
+
+func (c *unsampledCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.raw.Write(ent, fields)
+}
+
+func (c *unsampledCore) Sync() error {
+	return c.raw.Sync()
+}
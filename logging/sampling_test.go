@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithSampling(t *testing.T) {
+	tests := []struct {
+		name                string
+		opts                []Option
+		wantInfo, wantError int
+	}{
+		{"default", nil, 109, 109},
+		{"custom", []Option{WithSampling(10, 100)}, 19, 19},
+		{"off", []Option{WithSampling(0, 0)}, 1000, 1000},
+		{"except error", []Option{WithSamplingExcept(zapcore.ErrorLevel, 10, 100)}, 19, 1000},
+		{"except warn", []Option{WithSamplingExcept(zapcore.WarnLevel, 10, 100)}, 19, 1000},
+		{"except then sampling", []Option{WithSamplingExcept(zapcore.ErrorLevel, 10, 100), WithSampling(10, 100)}, 19, 19},
+		{"except, off", []Option{WithSamplingExcept(zapcore.ErrorLevel, 0, 0)}, 1000, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, tt.opts...)
+			for i := 0; i < 1000; i++ {
+				logger.Info("same message")
+				logger.Error("same message")
+			}
+
+			counts := make(map[interface{}]int)
+			for _, entry := range entries() {
+				counts[entry["level"]]++
+			}
+			if counts["info"] != tt.wantInfo || counts["error"] != tt.wantError {
+				t.Errorf("got %d info and %d error entries, want %d and %d",
+					counts["info"], counts["error"], tt.wantInfo, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestWithSamplingExcept(t *testing.T) {
+	logger, entries := newFileLogger(t, WithSamplingExcept(zapcore.ErrorLevel, 100, 100))
+	child := logger.With(zap.String("component", "db"))
+	for i := 0; i < 200; i++ {
+		logger.Error("connection refused")
+		child.Error("query failed")
+		logger.Info("retrying")
+	}
+
+	counts := make(map[interface{}]int)
+	for _, entry := range entries() {
+		counts[entry["msg"]]++
+	}
+	tests := []struct {
+		msg  string
+		want int
+	}{
+		{"connection refused", 200},
+		{"query failed", 200},
+		// The first 100, then every 100th of the remaining 100.
+		{"retrying", 101},
+	}
+	for _, tt := range tests {
+		if counts[tt.msg] != tt.want {
+			t.Errorf("%q: got %d entries, want %d", tt.msg, counts[tt.msg], tt.want)
+		}
+	}
+}
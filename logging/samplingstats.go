@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingStats counts the decisions of a sampler. It is safe to read while
+// entries are being logged.
+type SamplingStats struct {
+	sampled atomic.Int64
+	dropped atomic.Int64
+}
+
+// Sampled returns the number of entries the sampler let through.
+func (s *SamplingStats) Sampled() int64 {
+	return s.sampled.Load()
+}
+
+// Dropped returns the number of entries the sampler dropped.
+func (s *SamplingStats) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Hook records a sampling decision. It has the signature of
+// zap.SamplingConfig.Hook and zapcore.SamplerHook, so s can also count the
+// decisions of the sampler a zap.Config builds.
+func (s *SamplingStats) Hook(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		s.dropped.Add(1)
+	} else {
+		s.sampled.Add(1)
+	}
+}
+
+// NewCountingSampler is zapcore.NewSamplerWithOptions with the decisions
+// counted in the returned SamplingStats: per tick, the first entries with the
+// same level and message are logged, then every thereafter-th one. Entries
+// below core's level are not counted, they never reach the sampler.
+func NewCountingSampler(core zapcore.Core, tick time.Duration, first, thereafter int) (zapcore.Core, *SamplingStats) {
+	stats := &SamplingStats{}
+	return zapcore.NewSamplerWithOptions(core, tick, first, thereafter, zapcore.SamplerHook(stats.Hook)), stats
+}
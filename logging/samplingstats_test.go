@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewCountingSampler(t *testing.T) {
+	tests := []struct {
+		name              string
+		first, thereafter int
+		level             zapcore.Level
+		wantSampled       int64
+		wantDropped       int64
+	}{
+		{"first then thereafter", 100, 100, zapcore.InfoLevel, 109, 891},
+		{"first only", 10, 0, zapcore.InfoLevel, 10, 990},
+		{"below the core's level", 100, 100, zapcore.DebugLevel, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observed, logs := observer.New(zapcore.InfoLevel)
+			core, stats := NewCountingSampler(observed, time.Hour, tt.first, tt.thereafter)
+			logger := zap.New(core)
+			for i := 0; i < 1000; i++ {
+				logger.Log(tt.level, "same message")
+			}
+
+			if stats.Sampled() != tt.wantSampled || stats.Dropped() != tt.wantDropped {
+				t.Errorf("Sampled, Dropped = %d, %d, want %d, %d", stats.Sampled(), stats.Dropped(), tt.wantSampled, tt.wantDropped)
+			}
+			if tt.level >= zapcore.InfoLevel && stats.Sampled()+stats.Dropped() != 1000 {
+				t.Errorf("Sampled + Dropped = %d, want the 1000 entries logged", stats.Sampled()+stats.Dropped())
+			}
+			if int64(logs.Len()) != stats.Sampled() {
+				t.Errorf("core got %d entries, want the %d sampled", logs.Len(), stats.Sampled())
+			}
+		})
+	}
+}
+
+func TestSamplingStatsConcurrent(t *testing.T) {
+	observed, _ := observer.New(zapcore.InfoLevel)
+	core, stats := NewCountingSampler(observed, time.Hour, 10, 10)
+	logger := zap.New(core)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				logger.Info("same message")
+				// Read while the others are still logging.
+				_ = stats.Sampled() + stats.Dropped()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if total := stats.Sampled() + stats.Dropped(); total != 8*500 {
+		t.Errorf("Sampled + Dropped = %d, want %d", total, 8*500)
+	}
+}
+
+func TestSamplingStatsHook(t *testing.T) {
+	stats := &SamplingStats{}
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = nil
+	cfg.Sampling = &zap.SamplingConfig{Initial: 5, Thereafter: 0, Hook: stats.Hook}
+	logger, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		logger.Info("same message")
+	}
+
+	if stats.Sampled() != 5 || stats.Dropped() != 15 {
+		t.Errorf("Sampled, Dropped = %d, %d, want 5, 15", stats.Sampled(), stats.Dropped())
+	}
+}
@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sanitizer rewrites a field before it is encoded, to enforce rules such as
+// which personal data may be logged. Sanitize returns the field unchanged
+// when there is nothing to do.
+type Sanitizer interface {
+	Sanitize(field zapcore.Field) zapcore.Field
+}
+
+// SanitizerFunc adapts a function to the Sanitizer interface.
+type SanitizerFunc func(field zapcore.Field) zapcore.Field
+
+// Sanitize calls f(field).
+func (f SanitizerFunc) Sanitize(field zapcore.Field) zapcore.Field {
+	return f(field)
+}
+
+// WithSanitizer passes every field, those added with With included, through
+// s before it is encoded. The option can be given several times; the
+// sanitizers then run in the order they were given, each receiving the
+// field the previous one returned, all within a single core.
+//
+// Sanitizers see the fields of the log call, not what is inside objects
+// logged with zap.Object or zap.Any; WithRedactedKeys covers keys at any
+// depth.
+func WithSanitizer(s Sanitizer) Option {
+	return func(o *options) {
+		if len(o.sanitizers) == 0 {
+			o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+				// Read at build time, so that it holds every WithSanitizer.
+				return &sanitizeCore{Core: core, sanitizers: o.sanitizers}
+			})
+		}
+		o.sanitizers = append(o.sanitizers, s)
+	}
+}
+
+type sanitizeCore struct {
+	zapcore.Core
+	sanitizers []Sanitizer
+}
+
+func (c *sanitizeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &sanitizeCore{Core: c.Core.With(c.sanitize(fields)), sanitizers: c.sanitizers}
+}
+
+func (c *sanitizeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sanitizeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.sanitize(fields))
+}
+
+// sanitize returns fields with every sanitizer applied, copying the slice
+// only once a sanitizer changes a field; the caller's slice is left alone.
+func (c *sanitizeCore) sanitize(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, orig := range fields {
+		f := orig
+		for _, s := range c.sanitizers {
+			f = s.Sanitize(f)
+		}
+		if sameField(f, orig) {
+			if out != nil {
+				out = append(out, orig)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, f)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// sameField reports whether a sanitizer handed back the field it was given.
+// Values it cannot compare cheaply count as changed, which only costs a copy.
+func sameField(a, b zapcore.Field) bool {
+	if a.Type != b.Type || a.Key != b.Key || a.Integer != b.Integer || a.String != b.String {
+		return false
+	}
+	switch x := a.Interface.(type) {
+	case nil:
+		return b.Interface == nil
+	case []byte:
+		y, ok := b.Interface.([]byte)
+		return ok && len(x) == len(y) && (len(x) == 0 || &x[0] == &y[0])
+	}
+	// Structs and arrays may hold values that panic when compared.
+	t := reflect.TypeOf(a.Interface)
+	if t != reflect.TypeOf(b.Interface) || !t.Comparable() || t.Kind() == reflect.Struct || t.Kind() == reflect.Array {
+		return false
+	}
+	return a.Interface == b.Interface
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// EmailSanitizer is a Sanitizer that masks the local part of the email
+// addresses in string and byte string fields, keeping its first character:
+// "john.doe@example.com" is logged as "j***@example.com", so that the
+// domain stays available for debugging. Addresses are found anywhere in the
+// value, not only when they make up all of it.
+type EmailSanitizer struct{}
+
+// Sanitize masks the email addresses in field.
+func (EmailSanitizer) Sanitize(field zapcore.Field) zapcore.Field {
+	switch field.Type {
+	case zapcore.StringType:
+		if strings.IndexByte(field.String, '@') >= 0 {
+			return zap.String(field.Key, maskEmails(field.String))
+		}
+	case zapcore.ByteStringType:
+		if b, ok := field.Interface.([]byte); ok && bytes.IndexByte(b, '@') >= 0 {
+			return zap.ByteString(field.Key, []byte(maskEmails(string(b))))
+		}
+	}
+	return field
+}
+
+func maskEmails(s string) string {
+	return emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+		at := strings.LastIndexByte(email, '@')
+		_, size := utf8.DecodeRuneInString(email)
+		return email[:size] + "***" + email[at:]
+	})
+}
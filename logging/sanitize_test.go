@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEmailSanitizer(t *testing.T) {
+	tests := []struct {
+		name  string
+		field zapcore.Field
+		want  zapcore.Field
+	}{
+		{"address", zap.String("email", "john.doe@example.com"), zap.String("email", "j***@example.com")},
+		{"in text", zap.String("note", "mail jane@corp.example.org re: refund"), zap.String("note", "mail j***@corp.example.org re: refund")},
+		{"several", zap.String("to", "a@x.io, bob@y.io"), zap.String("to", "a***@x.io, b***@y.io")},
+		{"byte string", zap.ByteString("raw", []byte("john@example.com")), zap.ByteString("raw", []byte("j***@example.com"))},
+		{"no address", zap.String("handle", "@john"), zap.String("handle", "@john")},
+		{"not a string", zap.Int("count", 3), zap.Int("count", 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (EmailSanitizer{}).Sanitize(tt.field); !got.Equals(tt.want) {
+				t.Errorf("Sanitize = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithSanitizer(t *testing.T) {
+	// hideMasked replaces values that an earlier sanitizer masked, so the
+	// result tells in which order the two ran.
+	hideMasked := SanitizerFunc(func(f zapcore.Field) zapcore.Field {
+		if f.Type == zapcore.StringType && strings.Contains(f.String, "***") {
+			return zap.String(f.Key, "[masked]")
+		}
+		return f
+	})
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"email", []Option{WithSanitizer(EmailSanitizer{})}, "j***@example.com"},
+		{"chained", []Option{WithSanitizer(EmailSanitizer{}), WithSanitizer(hideMasked)}, "[masked]"},
+		{"chained, reversed", []Option{WithSanitizer(hideMasked), WithSanitizer(EmailSanitizer{})}, "j***@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, tt.opts...)
+			fields := []zap.Field{zap.String("email", "john@example.com")}
+			logger.With(zap.String("owner", "john@example.com")).Info("signed up", fields...)
+
+			e := entries()[0]
+			for _, key := range []string{"email", "owner"} {
+				if e[key] != tt.want {
+					t.Errorf("%s = %v, want %q", key, e[key], tt.want)
+				}
+			}
+			if fields[0].String != "john@example.com" {
+				t.Errorf("the caller's field was changed to %q", fields[0].String)
+			}
+		})
+	}
+}
+
+func TestSanitizeCopiesOnChange(t *testing.T) {
+	c := &sanitizeCore{sanitizers: []Sanitizer{EmailSanitizer{}}}
+	clean := []zapcore.Field{
+		zap.String("user", "alice"),
+		zap.ByteString("body", []byte(strings.Repeat("order ", 10))),
+		zap.Int("status", 200),
+		zap.Error(io.EOF),
+	}
+	if allocs := testing.AllocsPerRun(100, func() { c.sanitize(clean) }); allocs != 0 {
+		t.Errorf("nothing to sanitize: %v allocs, want 0", allocs)
+	}
+
+	fields := []zapcore.Field{zap.Int("status", 200), zap.String("email", "john.doe@example.com")}
+	got := c.sanitize(fields)
+	if got[1].String != "j***@example.com" || got[0].Integer != 200 {
+		t.Errorf("sanitize = %v", got)
+	}
+	if fields[1].String != "john.doe@example.com" {
+		t.Errorf("caller's field changed to %q", fields[1].String)
+	}
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scope collects fields over the course of a unit of work, such as a
+// request, and logs them all in a single entry once it is done. It is safe
+// for concurrent use, so several goroutines working on the same request can
+// add to one Scope.
+type Scope struct {
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	fields []zap.Field
+}
+
+// NewScope returns an empty Scope that logs through l.
+func NewScope(l *zap.Logger) *Scope {
+	return &Scope{logger: helperLogger(l)}
+}
+
+// Add appends fields to the next entry Flush writes.
+func (s *Scope) Add(fields ...zap.Field) {
+	s.mu.Lock()
+	s.fields = append(s.fields, fields...)
+	s.mu.Unlock()
+}
+
+// Flush logs msg at level with every field added since the previous Flush,
+// then starts over with no fields.
+func (s *Scope) Flush(level zapcore.Level, msg string) {
+	s.mu.Lock()
+	fields := s.fields
+	s.fields = nil
+	s.mu.Unlock()
+
+	if ce := s.logger.Check(level, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestScope(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	s := NewScope(logger)
+	s.Add(zap.String("user", "frank"))
+	s.Add(zap.Int("items", 3), zap.Bool("cached", true))
+	s.Flush(zapcore.InfoLevel, "checkout")
+	s.Add(zap.String("user", "anna"))
+	s.Flush(zapcore.WarnLevel, "retry")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	tests := []struct {
+		entry int
+		want  map[string]interface{}
+	}{
+		{0, map[string]interface{}{"user": "frank", "items": int64(3), "cached": true}},
+		{1, map[string]interface{}{"user": "anna"}},
+	}
+	for _, tt := range tests {
+		got := entries[tt.entry].ContextMap()
+		if len(got) != len(tt.want) {
+			t.Errorf("entry %d has fields %v, want %v", tt.entry, got, tt.want)
+		}
+		for key, v := range tt.want {
+			if got[key] != v {
+				t.Errorf("entry %d: %s = %v, want %v", tt.entry, key, got[key], v)
+			}
+		}
+	}
+}
+
+func TestScopeConcurrentAdd(t *testing.T) {
+	const goroutines, adds = 8, 50
+	logger, logs := logtest.NewTestLogger()
+	s := NewScope(logger)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < adds; i++ {
+				s.Add(zap.Int(fmt.Sprintf("g%d_%d", g, i), i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	s.Flush(zapcore.InfoLevel, "done")
+
+	if got := len(logs.All()[0].Context); got != goroutines*adds {
+		t.Errorf("entry has %d fields, want %d", got, goroutines*adds)
+	}
+}
+
+func TestScopeConcurrentFlush(t *testing.T) {
+	const goroutines, adds = 8, 50
+	logger, logs := logtest.NewTestLogger()
+	s := NewScope(logger)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < adds; i++ {
+				s.Add(zap.Int("i", i))
+				if i%10 == 0 {
+					s.Flush(zapcore.InfoLevel, "partial")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	s.Flush(zapcore.InfoLevel, "partial")
+
+	total := 0
+	for _, e := range logs.All() {
+		total += len(e.Context)
+	}
+	if total != goroutines*adds {
+		t.Errorf("flushed %d fields in all, want every one of the %d added exactly once", total, goroutines*adds)
+	}
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap/zapcore"
+)
+
+// sentryFlushTimeout bounds how long Sync waits for queued Sentry events.
+const sentryFlushTimeout = 2 * time.Second
+
+// NewSentryCore returns a core that reports entries at or above minLevel to
+// Sentry through hub, or sentry.CurrentHub() if hub is nil. Each entry
+// becomes an event with the entry's message and level, its fields as extra
+// data, and an exception for every error field.
+//
+// Errors sending to Sentry are never returned, so that combining the core
+// with zapcore.NewTee cannot affect regular logging.
+func NewSentryCore(hub *sentry.Hub, minLevel zapcore.Level) zapcore.Core {
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return &sentryCore{LevelEnabler: minLevel, hub: hub}
+}
+
+type sentryCore struct {
+	zapcore.LevelEnabler
+	hub    *sentry.Hub
+	fields []zapcore.Field
+}
+
+func (c *sentryCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+func (c *sentryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sentryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	event := sentry.NewEvent()
+	event.Level = sentryLevel(ent.Level)
+	event.Message = ent.Message
+	event.Timestamp = ent.Time
+	event.Logger = ent.LoggerName
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range append(c.fields[:len(c.fields):len(c.fields)], fields...) {
+		if f.Type == zapcore.ErrorType {
+			if err, ok := f.Interface.(error); ok && err != nil {
+				event.Exception = append(event.Exception, sentry.Exception{
+					Type:       reflect.TypeOf(err).String(),
+					Value:      err.Error(),
+					Stacktrace: sentry.ExtractStacktrace(err),
+				})
+			}
+		}
+		f.AddTo(enc)
+	}
+	event.Extra = enc.Fields
+
+	c.hub.CaptureEvent(event)
+	return nil
+}
+
+func (c *sentryCore) Sync() error {
+	c.hub.Flush(sentryFlushTimeout)
+	return nil
+}
+
+func sentryLevel(l zapcore.Level) sentry.Level {
+	switch l {
+	case zapcore.DebugLevel:
+		return sentry.LevelDebug
+	case zapcore.InfoLevel:
+		return sentry.LevelInfo
+	case zapcore.WarnLevel:
+		return sentry.LevelWarning
+	case zapcore.ErrorLevel:
+		return sentry.LevelError
+	default:
+		return sentry.LevelFatal
+	}
+}
@@ -0,0 +1,120 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeTransport is a sentry.Transport keeping the events sent through it.
+// Flush reports failure when failing is set, like a transport that cannot
+// reach Sentry.
+type fakeTransport struct {
+	mu      sync.Mutex
+	events  []*sentry.Event
+	failing bool
+}
+
+func (t *fakeTransport) Configure(sentry.ClientOptions) {}
+
+func (t *fakeTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.failing {
+		t.events = append(t.events, event)
+	}
+}
+
+func (t *fakeTransport) Flush(time.Duration) bool {
+	return !t.failing
+}
+
+func (t *fakeTransport) sent() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.events
+}
+
+// newSentryHub returns a hub sending events through transport.
+func newSentryHub(t *testing.T, transport sentry.Transport) *sentry.Hub {
+	t.Helper()
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sentry.NewHub(client, sentry.NewScope())
+}
+
+func TestNewSentryCore(t *testing.T) {
+	tests := []struct {
+		level     zapcore.Level
+		wantLevel sentry.Level
+		wantSent  bool
+	}{
+		{zapcore.InfoLevel, "", false},
+		{zapcore.WarnLevel, sentry.LevelWarning, true},
+		{zapcore.ErrorLevel, sentry.LevelError, true},
+		{zapcore.DPanicLevel, sentry.LevelFatal, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			transport := &fakeTransport{}
+			core := NewSentryCore(newSentryHub(t, transport), zapcore.WarnLevel)
+			logger := zap.New(core).With(zap.String("service", "api"))
+
+			logger.Log(tt.level, "fetch failed", zap.Int("attempt", 3), zap.Error(errors.New("connection refused")))
+
+			events := transport.sent()
+			if !tt.wantSent {
+				if len(events) != 0 {
+					t.Errorf("got %d events, want none below the minimum level", len(events))
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			event := events[0]
+			if event.Message != "fetch failed" || event.Level != tt.wantLevel {
+				t.Errorf("event = %q at %s, want %q at %s", event.Message, event.Level, "fetch failed", tt.wantLevel)
+			}
+			wantExtra := map[string]interface{}{
+				"service": "api",
+				"attempt": int64(3),
+				"error":   "connection refused",
+			}
+			for key, v := range wantExtra {
+				if got := event.Extra[key]; got != v {
+					t.Errorf("extra %s = %v, want %v", key, got, v)
+				}
+			}
+			if len(event.Exception) != 1 || event.Exception[0].Value != "connection refused" {
+				t.Errorf("exception = %+v, want the logged error", event.Exception)
+			}
+		})
+	}
+}
+
+func TestSentryCoreDeliveryFailure(t *testing.T) {
+	sentryCore := NewSentryCore(newSentryHub(t, &fakeTransport{failing: true}), zapcore.ErrorLevel)
+	observed, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(zapcore.NewTee(sentryCore, observed))
+
+	logger.Error("fetch failed")
+
+	if logs.Len() != 1 {
+		t.Errorf("got %d entries in the other core, want 1", logs.Len())
+	}
+	if err := sentryCore.Write(zapcore.Entry{Level: zapcore.ErrorLevel, Message: "direct"}, nil); err != nil {
+		t.Errorf("Write = %v, want nil", err)
+	}
+	if err := sentryCore.Sync(); err != nil {
+		t.Errorf("Sync = %v, want nil", err)
+	}
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// RegisterShutdownFlush syncs l when the process receives SIGINT or SIGTERM,
+// so that buffered entries are not lost when a service is killed. After
+// syncing, the handler unregisters itself and re-delivers the signal, so the
+// process still terminates the way it would have without it.
+//
+// The logger is synced with SafeSync, and any error it still reports is
+// printed to standard error.
+//
+// The returned stop removes the handler without syncing. It is safe to call
+// more than once.
+func RegisterShutdownFlush(l *zap.Logger) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			if err := SafeSync(l); err != nil {
+				fmt.Fprintf(os.Stderr, "logging: sync on %v: %v\n", sig, err)
+			}
+			signal.Stop(signals)
+			if p, err := os.FindProcess(os.Getpid()); err == nil {
+				_ = p.Signal(sig)
+			}
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRegisterShutdownFlush(t *testing.T) {
+	tests := []struct {
+		name     string
+		stop     bool
+		wantSync bool
+	}{
+		{"signal", false, true},
+		{"stopped", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// With a channel of the test's own registered, the re-delivered
+			// signal does not terminate the test binary.
+			received := make(chan os.Signal, 2)
+			signal.Notify(received, syscall.SIGTERM)
+			defer signal.Stop(received)
+
+			ws := newFakeSyncer()
+			ws.err = syscall.EINVAL
+			stderr := redirectStderr(t)
+			stop := RegisterShutdownFlush(newSyncerLogger(ws))
+			defer stop()
+			if tt.stop {
+				stop()
+				stop()
+			}
+
+			if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+				t.Fatal(err)
+			}
+			<-received
+
+			select {
+			case <-ws.synced:
+				if !tt.wantSync {
+					t.Error("the logger was synced after stop")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantSync {
+					t.Fatal("the logger was not synced on SIGTERM")
+				}
+			}
+			if tt.wantSync {
+				// The sync reports EINVAL, which is not worth printing.
+				select {
+				case <-received:
+				case <-time.After(time.Second):
+					t.Error("SIGTERM was not re-delivered after syncing")
+				}
+				if out := stderr(); out != "" {
+					t.Errorf("printed %q for an EINVAL sync error", out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSlogHandler returns a slog.Handler that sends records to l's core, so
+// that libraries logging with log/slog end up in the same place as the rest
+// of the program:
+//
+//	slog.SetDefault(slog.New(logging.NewSlogHandler(logger)))
+//
+// slog levels map to the nearest zap level at or below them (Debug, Info,
+// Warn, Error). Attributes keep their types, groups become nested objects,
+// and the record's caller is reported instead of the handler's.
+//
+// Records are written to l's core directly, not through l. Hooks and fields
+// added to the core apply, but the options zap's Logger handles itself do
+// not: there is no stack trace, whatever zap.AddStacktrace says, and the
+// caller is attached whenever the record has one, even if l was built
+// without zap.AddCaller.
+func NewSlogHandler(l *zap.Logger) slog.Handler {
+	return &slogHandler{core: l.Core(), name: l.Name()}
+}
+
+type slogHandler struct {
+	core zapcore.Core
+	name string
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(zapLevel(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      zapLevel(r.Level),
+		Time:       r.Time,
+		LoggerName: h.name,
+		Message:    r.Message,
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		ent.Caller.Function = frame.Function
+	}
+
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	fields := make([]zapcore.Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, a)
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{core: h.core.With(appendSlogAttrs(nil, attrs)), name: h.name}
+}
+
+// WithGroup opens a zap namespace, which nests every field added afterwards,
+// by WithAttrs or per record, the same way slog groups do.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{core: h.core.With([]zapcore.Field{zap.Namespace(name)}), name: h.name}
+}
+
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func appendSlogAttr(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, a.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, a.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, a.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, a.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, a.Value.Time()))
+	case slog.KindGroup:
+		group := slogGroup(a.Value.Group())
+		if len(group) == 0 {
+			return fields
+		}
+		// slog inlines the attributes of a group without a key.
+		if a.Key == "" {
+			return append(fields, zap.Inline(group))
+		}
+		return append(fields, zap.Object(a.Key, group))
+	default:
+		return append(fields, zap.Any(a.Key, a.Value.Any()))
+	}
+}
+
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range appendSlogAttrs(nil, g) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+func appendSlogAttrs(fields []zapcore.Field, attrs []slog.Attr) []zapcore.Field {
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, a)
+	}
+	return fields
+}
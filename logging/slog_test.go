@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestSlogHandlerLevels(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  zapcore.Level
+	}{
+		{slog.LevelDebug - 4, zapcore.DebugLevel},
+		{slog.LevelDebug, zapcore.DebugLevel},
+		{slog.LevelInfo, zapcore.InfoLevel},
+		{slog.LevelInfo + 2, zapcore.InfoLevel},
+		{slog.LevelWarn, zapcore.WarnLevel},
+		{slog.LevelError, zapcore.ErrorLevel},
+		{slog.LevelError + 4, zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			logger, logs := logtest.NewTestLogger()
+			slog.New(NewSlogHandler(logger)).Log(context.Background(), tt.level, "hi")
+			logtest.AssertLogged(t, logs, tt.want, "hi")
+		})
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	logger := slog.New(NewSlogHandler(zap.New(core)))
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	if n := logs.Len(); n != 1 {
+		t.Errorf("got %d entries, want 1", n)
+	}
+}
+
+func TestSlogHandlerAttrs(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	slog.New(NewSlogHandler(logger.Named("lib"))).Info("fetched",
+		slog.String("url", "http://marmotedu.com"),
+		slog.Int("attempt", 3),
+		slog.Uint64("bytes", 512),
+		slog.Float64("ratio", 0.5),
+		slog.Bool("cached", true),
+		slog.Duration("backoff", time.Second),
+		slog.Any("tags", []string{"a"}),
+	)
+
+	entry := logs.All()[0]
+	tests := []struct {
+		key  string
+		want zapcore.FieldType
+	}{
+		{"url", zapcore.StringType},
+		{"attempt", zapcore.Int64Type},
+		{"bytes", zapcore.Uint64Type},
+		{"ratio", zapcore.Float64Type},
+		{"cached", zapcore.BoolType},
+		{"backoff", zapcore.DurationType},
+		{"tags", zapcore.ArrayMarshalerType},
+	}
+	types := make(map[string]zapcore.FieldType)
+	for _, f := range entry.Context {
+		types[f.Key] = f.Type
+	}
+	for _, tt := range tests {
+		if types[tt.key] != tt.want {
+			t.Errorf("%s has type %v, want %v", tt.key, types[tt.key], tt.want)
+		}
+	}
+	if entry.LoggerName != "lib" {
+		t.Errorf("LoggerName = %q, want lib", entry.LoggerName)
+	}
+	if !entry.Caller.Defined || filepath.Base(entry.Caller.File) != "slog_test.go" {
+		t.Errorf("caller = %v, want this file", entry.Caller)
+	}
+}
+
+func TestSlogHandlerGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "group attr",
+			log:  func(l *slog.Logger) { l.Info("hi", slog.Group("request", slog.String("method", "GET"))) },
+			want: `{"request":{"method":"GET"}}`,
+		},
+		{
+			name: "inline group",
+			log:  func(l *slog.Logger) { l.Info("hi", slog.Group("", slog.String("method", "GET"))) },
+			want: `{"method":"GET"}`,
+		},
+		{
+			name: "empty group",
+			log:  func(l *slog.Logger) { l.Info("hi", slog.Group("request")) },
+			want: `{}`,
+		},
+		{
+			name: "WithGroup",
+			log: func(l *slog.Logger) {
+				l.WithGroup("request").With(slog.String("method", "GET")).Info("hi", slog.Int("status", 200))
+			},
+			want: `{"request":{"method":"GET","status":200}}`,
+		},
+		{
+			name: "WithAttrs before WithGroup",
+			log: func(l *slog.Logger) {
+				l.With(slog.String("service", "demo")).WithGroup("request").Info("hi", slog.Int("status", 200))
+			},
+			want: `{"service":"demo","request":{"status":200}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			tt.log(slog.New(NewSlogHandler(logger)))
+
+			entry := entries()[0]
+			for _, key := range []string{"level", "ts", "caller", "msg"} {
+				delete(entry, key)
+			}
+			got, _ := json.Marshal(entry)
+			if !jsonEqual(t, string(got), tt.want) {
+				t.Errorf("fields = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
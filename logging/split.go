@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSplitLogger builds a JSON logger that writes Debug and Info entries to
+// standard output and Warn and above to standard error. Each entry goes to
+// exactly one of the two streams, which suits log collectors that treat
+// stdout and stderr differently.
+func NewSplitLogger() *zap.Logger {
+	return newSplitLogger(zapcore.Lock(os.Stdout), zapcore.Lock(os.Stderr))
+}
+
+func newSplitLogger(stdout, stderr zapcore.WriteSyncer) *zap.Logger {
+	low := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl < zapcore.WarnLevel
+	})
+	high := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl >= zapcore.WarnLevel
+	})
+
+	enc := zapcore.NewJSONEncoder(newOptions().config.EncoderConfig)
+	core := zapcore.NewTee(
+		zapcore.NewCore(enc, stdout, low),
+		zapcore.NewCore(enc.Clone(), stderr, high),
+	)
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewSplitLogger(t *testing.T) {
+	tests := []struct {
+		msg string
+		log func(l *zap.Logger, msg string, fields ...zap.Field)
+		// toStderr reports whether the entry belongs on standard error.
+		toStderr bool
+	}{
+		{"debug entry", (*zap.Logger).Debug, false},
+		{"info entry", (*zap.Logger).Info, false},
+		{"warn entry", (*zap.Logger).Warn, true},
+		{"error entry", (*zap.Logger).Error, true},
+	}
+	stdout, stderr := newFakeSyncer(), newFakeSyncer()
+	logger := newSplitLogger(stdout, stderr)
+	for _, tt := range tests {
+		tt.log(logger, tt.msg)
+	}
+
+	for _, tt := range tests {
+		msg := `"msg":"` + tt.msg + `"`
+		inStdout := strings.Count(stdout.String(), msg)
+		inStderr := strings.Count(stderr.String(), msg)
+		wantStdout, wantStderr := 1, 0
+		if tt.toStderr {
+			wantStdout, wantStderr = 0, 1
+		}
+		if inStdout != wantStdout || inStderr != wantStderr {
+			t.Errorf("%s: %d on stdout and %d on stderr, want %d and %d", tt.msg, inStdout, inStderr, wantStdout, wantStderr)
+		}
+	}
+}
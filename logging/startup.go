@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// timeLayoutSample is the time.Format reference time. Encoded with a layout
+// based time encoder it reproduces the layout itself.
+var timeLayoutSample = time.Date(2006, time.January, 2, 15, 4, 5, 0, time.FixedZone("MST", -7*60*60))
+
+// LogStartupConfig logs one InfoLevel entry describing cfg, the config l was
+// built from: its level, encoding, output paths, sampling and how timestamps
+// look, the latter as the reference time 2006-01-02 15:04:05 -0700 encoded
+// with cfg's time encoder. The settings are nested under "config", each with
+// a key of its own, so a value that must not show up in the logs can be
+// hidden with WithRedactedKeys.
+func LogStartupConfig(l *zap.Logger, cfg zap.Config) {
+	fields := []zap.Field{
+		// Nested, so that "level" does not clash with the entry's own level.
+		zap.Namespace("config"),
+		zap.String("level", levelString(cfg.Level)),
+		zap.String("encoding", cfg.Encoding),
+		zap.Bool("development", cfg.Development),
+		zap.Strings("output_paths", cfg.OutputPaths),
+		zap.Strings("error_output_paths", cfg.ErrorOutputPaths),
+	}
+	if s := cfg.Sampling; s != nil {
+		fields = append(fields, zap.Object("sampling", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddInt("initial", s.Initial)
+			enc.AddInt("thereafter", s.Thereafter)
+			return nil
+		})))
+	} else {
+		fields = append(fields, zap.Bool("sampling", false))
+	}
+	if cfg.EncoderConfig.EncodeTime != nil {
+		fields = append(fields, zap.String("time_format", encodeSampleTime(cfg.EncoderConfig.EncodeTime)))
+	}
+	helperLogger(l).Info("logger configuration", fields...)
+}
+
+func levelString(lvl zap.AtomicLevel) string {
+	if lvl == (zap.AtomicLevel{}) {
+		return ""
+	}
+	return lvl.String()
+}
+
+// encodeSampleTime renders timeLayoutSample the way enc writes timestamps.
+func encodeSampleTime(enc zapcore.TimeEncoder) string {
+	m := zapcore.NewMapObjectEncoder()
+	_ = m.AddArray("t", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+		enc(timeLayoutSample, ae)
+		return nil
+	}))
+	if v, ok := m.Fields["t"].([]interface{}); ok && len(v) == 1 {
+		return fmt.Sprint(v[0])
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogStartupConfig(t *testing.T) {
+	custom := zap.NewProductionConfig()
+	custom.Level = zap.NewAtomicLevelAt(zapcore.WarnLevel)
+	custom.Encoding = "console"
+	custom.OutputPaths = []string{"/var/log/app.log"}
+	custom.Sampling = nil
+	custom.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(time.RFC1123)
+
+	tests := []struct {
+		name string
+		cfg  zap.Config
+		want map[string]interface{}
+	}{
+		{
+			name: "production",
+			cfg:  zap.NewProductionConfig(),
+			want: map[string]interface{}{
+				"level":        "info",
+				"encoding":     "json",
+				"development":  false,
+				"output_paths": []interface{}{"stderr"},
+				"sampling":     map[string]interface{}{"initial": float64(100), "thereafter": float64(100)},
+				"time_format":  "1.136239445e+09",
+			},
+		},
+		{
+			name: "custom",
+			cfg:  custom,
+			want: map[string]interface{}{
+				"level":        "warn",
+				"encoding":     "console",
+				"output_paths": []interface{}{"/var/log/app.log"},
+				"sampling":     false,
+				"time_format":  "Mon, 02 Jan 2006 15:04:05 MST",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			LogStartupConfig(logger, tt.cfg)
+
+			entry := entries()[0]
+			if entry["msg"] != "logger configuration" || entry["level"] != "info" {
+				t.Errorf("entry = %v, want an info entry", entry)
+			}
+			config, ok := entry["config"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("config = %v, want an object", entry["config"])
+			}
+			for key, v := range tt.want {
+				if !reflect.DeepEqual(config[key], v) {
+					t.Errorf("%s = %v, want %v", key, config[key], v)
+				}
+			}
+		})
+	}
+}
+
+func TestLogStartupConfigRedacted(t *testing.T) {
+	logger, entries := newFileLogger(t, WithRedactedKeys("encoding"))
+	LogStartupConfig(logger, zap.NewProductionConfig())
+
+	config := entries()[0]["config"].(map[string]interface{})
+	if config["encoding"] != redacted {
+		t.Errorf("encoding = %v, want it redacted", config["encoding"])
+	}
+}
@@ -0,0 +1,20 @@
+package logging
+
+import (
+	"log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewStdLogger returns a standard library *log.Logger, for libraries that
+// take one, that logs every line through l at level, with the line as the
+// entry's message. The caller is the code calling the *log.Logger, not the
+// adapter. A level zap does not define falls back to InfoLevel.
+func NewStdLogger(l *zap.Logger, level zapcore.Level) *log.Logger {
+	std, err := zap.NewStdLogAt(l, level)
+	if err != nil {
+		return zap.NewStdLog(l)
+	}
+	return std
+}
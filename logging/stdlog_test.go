@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewStdLogger(t *testing.T) {
+	tests := []struct {
+		level zapcore.Level
+		want  string
+	}{
+		{zapcore.DebugLevel, "debug"},
+		{zapcore.WarnLevel, "warn"},
+		{zapcore.ErrorLevel, "error"},
+		{zapcore.Level(42), "info"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithLevel(zapcore.DebugLevel), WithShortCaller())
+			std := NewStdLogger(logger, tt.level)
+			want := here(1)
+			std.Printf("connection %d reset", 7)
+
+			got := entries()
+			if len(got) != 1 {
+				t.Fatalf("got %d entries, want 1", len(got))
+			}
+			entry := got[0]
+			if entry["msg"] != "connection 7 reset" || entry["level"] != tt.want {
+				t.Errorf("entry = %v, want message %q at %s", entry, "connection 7 reset", tt.want)
+			}
+			if entry["caller"] != want {
+				t.Errorf("caller = %v, want the Printf call at %s", entry["caller"], want)
+			}
+		})
+	}
+}
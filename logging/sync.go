@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// SafeSync syncs l and returns the errors worth reporting. fsync fails with
+// EINVAL or ENOTTY on a terminal or pipe, so syncing a logger that writes to
+// stdout or stderr commonly reports "sync /dev/stderr: invalid argument";
+// SafeSync drops those errors and keeps every other one, so that genuine
+// failures, like a full disk, can still be logged or returned.
+func SafeSync(l *zap.Logger) error {
+	return ignoreStdSyncErr(l.Sync())
+}
+
+// ErrSyncTimeout is the error SyncWithTimeout wraps when l.Sync did not
+// return in time.
+var ErrSyncTimeout = errors.New("logging: sync timed out")
+
+// SyncWithTimeout calls l.Sync and returns its error, or an error wrapping
+// ErrSyncTimeout if it has not returned within d, so that a sink blocked on
+// an unreachable network peer cannot hang shutdown. A logger whose sinks sync
+// quickly, like local files, returns as soon as Sync does.
+//
+// Go has no way to interrupt the Sync call, so on timeout it is abandoned
+// rather than stopped: it keeps running in its own goroutine, which exits
+// once Sync eventually returns and whose result is then discarded. If the
+// sink never unblocks, that goroutine lives until the program exits, which is
+// normally about to happen when SyncWithTimeout is used. Entries still in
+// flight in an abandoned Sync may be lost.
+func SyncWithTimeout(l *zap.Logger, d time.Duration) error {
+	// Buffered, so the goroutine can deliver its result and exit even when
+	// no one is waiting for it any more.
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Sync()
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w after %v", ErrSyncTimeout, d)
+	}
+}
+
+// ignoreStdSyncErr removes the EINVAL and ENOTTY errors from err, looking
+// through errors combined by zap and wrapped ones such as *os.PathError.
+func ignoreStdSyncErr(err error) error {
+	var kept error
+	for _, e := range multierr.Errors(err) {
+		var errno syscall.Errno
+		if errors.As(e, &errno) && (errno == syscall.EINVAL || errno == syscall.ENOTTY) {
+			continue
+		}
+		kept = multierr.Append(kept, e)
+	}
+	return kept
+}
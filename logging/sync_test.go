@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSafeSync(t *testing.T) {
+	diskFull := &os.PathError{Op: "sync", Path: "/var/log/app.log", Err: syscall.ENOSPC}
+	tests := []struct {
+		name string
+		// errs are the errors returned by the logger's syncers.
+		errs []error
+		want []error
+	}{
+		{"ok", []error{nil}, nil},
+		{"EINVAL", []error{syscall.EINVAL}, nil},
+		{"wrapped EINVAL", []error{&os.PathError{Op: "sync", Path: "/dev/stderr", Err: syscall.EINVAL}}, nil},
+		{"ENOTTY", []error{&os.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.ENOTTY}}, nil},
+		{"genuine failure", []error{diskFull}, []error{diskFull}},
+		{"mixed", []error{syscall.EINVAL, diskFull}, []error{diskFull}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cores := make([]zapcore.Core, 0, len(tt.errs))
+			for _, err := range tt.errs {
+				ws := newFakeSyncer()
+				ws.err = err
+				cores = append(cores, newSyncerLogger(ws).Core())
+			}
+
+			err := SafeSync(zap.New(zapcore.NewTee(cores...)))
+
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("SafeSync = %v, want nil", err)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("SafeSync = %v, want it to include %v", err, want)
+				}
+			}
+			if errors.Is(err, syscall.EINVAL) {
+				t.Errorf("SafeSync = %v, want EINVAL dropped", err)
+			}
+		})
+	}
+}
+
+func TestSyncWithTimeout(t *testing.T) {
+	diskFull := &os.PathError{Op: "sync", Path: "/var/log/app.log", Err: syscall.ENOSPC}
+	tests := []struct {
+		name  string
+		delay time.Duration
+		err   error
+		want  error
+	}{
+		{"fast", 0, nil, nil},
+		{"fast failure", 0, diskFull, diskFull},
+		{"slow", time.Second, nil, ErrSyncTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newFakeSyncer()
+			ws.delay, ws.err = tt.delay, tt.err
+			logger := newSyncerLogger(ws)
+
+			start := time.Now()
+			err := SyncWithTimeout(logger, 50*time.Millisecond)
+			elapsed := time.Since(start)
+
+			if tt.want == nil && err != nil {
+				t.Errorf("SyncWithTimeout = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("SyncWithTimeout = %v, want %v", err, tt.want)
+			}
+			if elapsed > 500*time.Millisecond {
+				t.Errorf("SyncWithTimeout took %v, want it to return by the timeout", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log/syslog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogTimeLayout is the RFC 5424 TIMESTAMP, at its maximum of microsecond
+// precision.
+const syslogTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+var errSyslogClosed = errors.New("logging: syslog syncer is closed")
+
+// NewSyslogSyncer returns a WriteSyncer that sends every written JSON line to
+// syslog as a message tagged with tag, at the priority matching the line's
+// level: DebugLevel is LOG_DEBUG, InfoLevel LOG_INFO, WarnLevel LOG_WARNING,
+// ErrorLevel LOG_ERR, and DPanicLevel and above LOG_CRIT, all in the
+// LOG_USER facility.
+//
+// With an empty network the message goes to the local syslog daemon through
+// log/syslog. Otherwise the syncer connects to addr over network, such as
+// "udp" or "tcp", and writes complete RFC 5424 frames with the priority, a
+// timestamp, the hostname, tag and process ID; over TCP, frames are
+// delimited by octet counting, as RFC 6587 describes. A dropped connection
+// is dialed again on the next write.
+//
+// Failing to connect is returned as an error. The returned syncer has a
+// Close method that closes the connection.
+func NewSyslogSyncer(network, addr, tag string) (zapcore.WriteSyncer, error) {
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	if network == "" {
+		w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, tag)
+		if err != nil {
+			return nil, fmt.Errorf("logging: connect to syslog: %w", err)
+		}
+		return &localSyslogSyncer{w: w}, nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	s := &remoteSyslogSyncer{
+		network:  network,
+		addr:     addr,
+		hostname: hostname,
+		tag:      tag,
+		pid:      strconv.Itoa(os.Getpid()),
+		framed:   strings.HasPrefix(network, "tcp"),
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func syslogSeverity(level zapcore.Level) syslog.Priority {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return syslog.LOG_DEBUG
+	case level == zapcore.InfoLevel:
+		return syslog.LOG_INFO
+	case level == zapcore.WarnLevel:
+		return syslog.LOG_WARNING
+	case level == zapcore.ErrorLevel:
+		return syslog.LOG_ERR
+	default:
+		return syslog.LOG_CRIT
+	}
+}
+
+type localSyslogSyncer struct {
+	w *syslog.Writer
+}
+
+func (s *localSyslogSyncer) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	var err error
+	switch syslogSeverity(lineLevel(p)) {
+	case syslog.LOG_DEBUG:
+		err = s.w.Debug(msg)
+	case syslog.LOG_INFO:
+		err = s.w.Info(msg)
+	case syslog.LOG_WARNING:
+		err = s.w.Warning(msg)
+	case syslog.LOG_ERR:
+		err = s.w.Err(msg)
+	default:
+		err = s.w.Crit(msg)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("logging: write to syslog: %w", err)
+	}
+	return len(p), nil
+}
+
+// Sync does nothing: messages are sent as they are written.
+func (s *localSyslogSyncer) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (s *localSyslogSyncer) Close() error {
+	return s.w.Close()
+}
+
+type remoteSyslogSyncer struct {
+	network, addr string
+	hostname, tag string
+	pid           string
+	// framed prefixes frames with their length, for stream transports.
+	framed bool
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+func (s *remoteSyslogSyncer) connect() error {
+	conn, err := net.DialTimeout(s.network, s.addr, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("logging: connect to syslog at %s: %w", s.addr, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *remoteSyslogSyncer) Write(p []byte) (int, error) {
+	frame := s.frame(lineLevel(p), time.Now(), strings.TrimRight(string(p), "\n"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, errSyslogClosed
+	}
+	if s.conn != nil {
+		if _, err := s.conn.Write(frame); err == nil {
+			return len(p), nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return 0, err
+	}
+	if _, err := s.conn.Write(frame); err != nil {
+		return 0, fmt.Errorf("logging: write to syslog: %w", err)
+	}
+	return len(p), nil
+}
+
+// frame formats msg as an RFC 5424 message:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *remoteSyslogSyncer) frame(level zapcore.Level, t time.Time, msg string) []byte {
+	pri := syslog.LOG_USER | syslogSeverity(level)
+	line := fmt.Sprintf("<%d>1 %s %s %s %s - - %s",
+		pri, t.Format(syslogTimeLayout), s.hostname, s.tag, s.pid, msg)
+	if s.framed {
+		line = strconv.Itoa(len(line)) + " " + line
+	}
+	return []byte(line)
+}
+
+// Sync does nothing: frames are sent as they are written.
+func (s *remoteSyslogSyncer) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the syslog server.
+func (s *remoteSyslogSyncer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errSyslogClosed
+	}
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,150 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// rfc5424Frame matches an RFC 5424 message sent by a remote syslog syncer,
+// capturing its PRI and MSG.
+var rfc5424Frame = regexp.MustCompile(`^<(\d+)>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(Z|[+-]\d{2}:\d{2}) \S+ demo ` +
+	strconv.Itoa(os.Getpid()) + ` - - (.*)$`)
+
+func TestSyslogSyncerUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ws, err := NewSyslogSyncer("udp", conn.LocalAddr().String(), "demo")
+	if err != nil {
+		t.Fatalf("NewSyslogSyncer: %v", err)
+	}
+	defer ws.(interface{ Close() error }).Close()
+	logger := newSyncerLogger(ws)
+
+	tests := []struct {
+		level zapcore.Level
+		pri   int
+	}{
+		{zapcore.DebugLevel, 15},
+		{zapcore.InfoLevel, 14},
+		{zapcore.WarnLevel, 12},
+		{zapcore.ErrorLevel, 11},
+		{zapcore.DPanicLevel, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			logger.Check(tt.level, "disk almost full").Write()
+
+			buf := make([]byte, 64*1024)
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := rfc5424Frame.FindStringSubmatch(string(buf[:n]))
+			if m == nil {
+				t.Fatalf("frame %q is not an RFC 5424 message", buf[:n])
+			}
+			if m[1] != strconv.Itoa(tt.pri) {
+				t.Errorf("PRI = %s, want %d", m[1], tt.pri)
+			}
+			if entry := decodeLine(t, m[3]); entry["msg"] != "disk almost full" {
+				t.Errorf("MSG = %s, want the JSON entry", m[3])
+			}
+		})
+	}
+}
+
+func TestSyslogSyncerTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	frames := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			var size int
+			if _, err := fmt.Fscanf(r, "%d ", &size); err != nil {
+				return
+			}
+			frame := make([]byte, size)
+			if _, err := io.ReadFull(r, frame); err != nil {
+				return
+			}
+			frames <- string(frame)
+		}
+	}()
+
+	ws, err := NewSyslogSyncer("tcp", ln.Addr().String(), "demo")
+	if err != nil {
+		t.Fatalf("NewSyslogSyncer: %v", err)
+	}
+	defer ws.(interface{ Close() error }).Close()
+	logger := newSyncerLogger(ws)
+	logger.Info("first")
+	logger.Warn("second")
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case frame := <-frames:
+			m := rfc5424Frame.FindStringSubmatch(frame)
+			if m == nil || !strings.Contains(m[3], want) {
+				t.Errorf("frame %q is not an octet-counted RFC 5424 message for %q", frame, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no frame for %q", want)
+		}
+	}
+}
+
+func TestSyslogSyncerConnectFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := NewSyslogSyncer("tcp", addr, "demo"); err == nil {
+		t.Error("NewSyslogSyncer succeeded without a server")
+	}
+}
+
+func TestSyslogSyncerClosed(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ws, err := NewSyslogSyncer("udp", conn.LocalAddr().String(), "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.(interface{ Close() error }).Close()
+
+	if _, err := ws.Write([]byte(`{"level":"info"}` + "\n")); !errors.Is(err, errSyslogClosed) {
+		t.Errorf("Write after Close = %v, want errSyslogClosed", err)
+	}
+}
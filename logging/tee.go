@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewTeeLogger builds a logger that writes every entry to two places: colored,
+// human-readable console output on standard error for entries at or above
+// consoleLevel, and the usual JSON encoding appended to filePath for entries
+// at or above fileLevel.
+//
+// A typical development setup passes DebugLevel for the console and InfoLevel
+// for the file, so debug noise never reaches the file.
+func NewTeeLogger(consoleLevel, fileLevel zapcore.Level, filePath string) (*zap.Logger, error) {
+	file, _, err := zap.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fileConfig := newOptions().config.EncoderConfig
+	consoleConfig := fileConfig
+	consoleConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+
+	core := zapcore.NewTee(
+		zapcore.NewCore(zapcore.NewConsoleEncoder(consoleConfig), zapcore.Lock(os.Stderr), consoleLevel),
+		zapcore.NewCore(zapcore.NewJSONEncoder(fileConfig), file, fileLevel),
+	)
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), nil
+}
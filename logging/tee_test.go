@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewTeeLogger(t *testing.T) {
+	stderr := redirectStderr(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := NewTeeLogger(zapcore.DebugLevel, zapcore.InfoLevel, path)
+	if err != nil {
+		t.Fatalf("NewTeeLogger: %v", err)
+	}
+	logger.Debug("debug entry")
+	logger.Info("info entry")
+	_ = logger.Sync()
+
+	console := stderr()
+	var file []string
+	for _, entry := range readEntries(t, path) {
+		file = append(file, entry["msg"].(string))
+	}
+	tests := []struct {
+		msg               string
+		inConsole, inFile bool
+	}{
+		{"debug entry", true, false},
+		{"info entry", true, true},
+	}
+	for _, tt := range tests {
+		if got := strings.Contains(console, tt.msg); got != tt.inConsole {
+			t.Errorf("%q in console output = %v, want %v", tt.msg, got, tt.inConsole)
+		}
+		inFile := false
+		for _, msg := range file {
+			inFile = inFile || msg == tt.msg
+		}
+		if inFile != tt.inFile {
+			t.Errorf("%q in file = %v, want %v", tt.msg, inFile, tt.inFile)
+		}
+	}
+	if !strings.Contains(console, "\x1b[") {
+		t.Errorf("console output %q has no colored level", console)
+	}
+}
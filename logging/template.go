@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const templateKey = "template"
+
+// Templatef logs fmt.Sprintf(format, args...) at level, like the sugared
+// logger's Infof and friends, with format itself added as a "template"
+// field. Entries from the same call site then share a template whatever their
+// arguments, so log analytics can group and count them by it.
+//
+// The message is only formatted when l is going to write an entry at level,
+// and then just once.
+func Templatef(l *zap.Logger, level zapcore.Level, format string, args ...interface{}) {
+	// DPanic and above are always checked, they may panic or exit even when
+	// disabled.
+	if level < zapcore.DPanicLevel && !l.Core().Enabled(level) {
+		return
+	}
+	if ce := helperLogger(l).Check(level, fmt.Sprintf(format, args...)); ce != nil {
+		ce.Write(zap.String(templateKey, format))
+	}
+}
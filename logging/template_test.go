@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+// countingStringer counts how often it is formatted.
+type countingStringer struct{ n *int }
+
+func (s countingStringer) String() string {
+	*s.n++
+	return "user-42"
+}
+
+func TestTemplatef(t *testing.T) {
+	const format = "user %s bought %d items"
+	tests := []struct {
+		level       zapcore.Level
+		wantLogged  bool
+		wantFormats int
+	}{
+		{zapcore.DebugLevel, false, 0},
+		{zapcore.InfoLevel, true, 1},
+		{zapcore.ErrorLevel, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			var formats int
+			Templatef(zap.New(core), tt.level, format, countingStringer{&formats}, 3)
+
+			if formats != tt.wantFormats {
+				t.Errorf("message formatted %d times, want %d", formats, tt.wantFormats)
+			}
+			if !tt.wantLogged {
+				if logs.Len() != 0 {
+					t.Errorf("got %d entries, want none", logs.Len())
+				}
+				return
+			}
+			logtest.AssertLogged(t, logs, tt.level, "user user-42 bought 3 items")
+			if got, _ := logtest.FieldValue(logs.All()[0], "template"); got != format {
+				t.Errorf("template = %v, want %q", got, format)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// WithTraceContext returns a child of l carrying the trace_id and span_id of
+// the span active in ctx, in their canonical lowercase hex form, so that log
+// lines can be joined with traces. l is returned unchanged when ctx has no
+// valid span.
+func WithTraceContext(ctx context.Context, l *zap.Logger) *zap.Logger {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return l
+	}
+	return l.With(
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	)
+}
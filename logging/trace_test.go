@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"uber-zao-demo/logging/logtest"
+)
+
+func TestWithTraceContext(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantTraceID string
+		wantSpanID  string
+	}{
+		{
+			name:        "active span",
+			ctx:         trace.ContextWithSpanContext(context.Background(), sc),
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+		},
+		{
+			name: "no span",
+			ctx:  context.Background(),
+		},
+		{
+			name: "invalid span",
+			ctx:  trace.ContextWithSpanContext(context.Background(), trace.SpanContext{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := logtest.NewTestLogger()
+			l := WithTraceContext(tt.ctx, logger)
+			if tt.wantTraceID == "" && l != logger {
+				t.Error("WithTraceContext did not return the logger unchanged")
+			}
+			l.Info("traced")
+
+			entry := logs.All()[0]
+			traceID, hasTraceID := logtest.FieldValue(entry, "trace_id")
+			spanID, hasSpanID := logtest.FieldValue(entry, "span_id")
+			if tt.wantTraceID == "" {
+				if hasTraceID || hasSpanID {
+					t.Errorf("got trace_id %v and span_id %v, want neither", traceID, spanID)
+				}
+				return
+			}
+			if traceID != tt.wantTraceID {
+				t.Errorf("trace_id = %v, want %s", traceID, tt.wantTraceID)
+			}
+			if spanID != tt.wantSpanID {
+				t.Errorf("span_id = %v, want %s", spanID, tt.wantSpanID)
+			}
+		})
+	}
+}
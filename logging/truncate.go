@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const truncatedMarker = "...(truncated)"
+
+// WithMaxFieldLength cuts the message and every string or byte string field
+// longer than n bytes down to at most n bytes, followed by "...(truncated)".
+// Values are only cut between runes, so the result stays valid UTF-8.
+//
+// Build fails unless n is positive.
+func WithMaxFieldLength(n int) Option {
+	return func(o *options) {
+		if n <= 0 {
+			o.setErr(fmt.Errorf("logging: max field length must be positive, got %d", n))
+			return
+		}
+		o.wrappers = append(o.wrappers, func(core zapcore.Core) zapcore.Core {
+			return &truncateCore{Core: core, max: n}
+		})
+	}
+}
+
+type truncateCore struct {
+	zapcore.Core
+	max int
+}
+
+func (c *truncateCore) With(fields []zapcore.Field) zapcore.Core {
+	return &truncateCore{Core: c.Core.With(c.truncateFields(fields)), max: c.max}
+}
+
+func (c *truncateCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *truncateCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if s, ok := c.truncate(ent.Message); ok {
+		ent.Message = s
+	}
+	return c.Core.Write(ent, c.truncateFields(fields))
+}
+
+// truncateFields returns fields with long values cut, copying the slice only
+// if one of them needs it.
+func (c *truncateCore) truncateFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		var cut zapcore.Field
+		switch f.Type {
+		case zapcore.StringType:
+			s, ok := c.truncate(f.String)
+			if !ok {
+				continue
+			}
+			cut = zap.String(f.Key, s)
+		case zapcore.ByteStringType:
+			b, ok := c.truncateBytes(f.Interface.([]byte))
+			if !ok {
+				continue
+			}
+			cut = zap.ByteString(f.Key, b)
+		default:
+			continue
+		}
+		if out == nil {
+			out = append([]zapcore.Field(nil), fields...)
+		}
+		out[i] = cut
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (c *truncateCore) truncate(s string) (string, bool) {
+	if len(s) <= c.max {
+		return s, false
+	}
+	cut := c.max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + truncatedMarker, true
+}
+
+// truncateBytes is truncate for byte strings. It only allocates for a value
+// that is cut, and leaves b itself alone.
+func (c *truncateCore) truncateBytes(b []byte) ([]byte, bool) {
+	if len(b) <= c.max {
+		return b, false
+	}
+	cut := c.max
+	for cut > 0 && !utf8.RuneStart(b[cut]) {
+		cut--
+	}
+	return append(b[:cut:cut], truncatedMarker...), true
+}
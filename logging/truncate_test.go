@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+func TestWithMaxFieldLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		field zap.Field
+		want  string
+	}{
+		{"short", zap.String("body", "short"), "short"},
+		{"exact", zap.String("body", strings.Repeat("a", 100)), strings.Repeat("a", 100)},
+		{"long", zap.String("body", strings.Repeat("a", 10*1024)), strings.Repeat("a", 100) + truncatedMarker},
+		{"byte string", zap.ByteString("body", []byte(strings.Repeat("a", 10*1024))), strings.Repeat("a", 100) + truncatedMarker},
+		// "é" is two bytes, so the 100th byte is the middle of the 50th.
+		{"multi-byte", zap.String("body", "a"+strings.Repeat("é", 5000)), "a" + strings.Repeat("é", 49) + truncatedMarker},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithMaxFieldLength(100))
+			logger.Info("response", tt.field)
+			logger.With(tt.field).Info("with")
+
+			for _, entry := range entries() {
+				got, _ := entry["body"].(string)
+				if got != tt.want {
+					t.Errorf("%v: body is %d bytes, want %d: %q", entry["msg"], len(got), len(tt.want), got)
+				}
+				if !utf8.ValidString(got) {
+					t.Errorf("%v: body %q is not valid UTF-8", entry["msg"], got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithMaxFieldLengthMessage(t *testing.T) {
+	logger, entries := newFileLogger(t, WithMaxFieldLength(10))
+	logger.Info(strings.Repeat("m", 50), zap.Int("status", 200))
+
+	entry := entries()[0]
+	if want := strings.Repeat("m", 10) + truncatedMarker; entry["msg"] != want {
+		t.Errorf("msg = %v, want %q", entry["msg"], want)
+	}
+	if entry["status"] != float64(200) {
+		t.Errorf("status = %v, want 200", entry["status"])
+	}
+}
+
+func TestWithMaxFieldLengthInvalid(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if _, err := NewLogger(WithMaxFieldLength(n)); err == nil {
+			t.Errorf("NewLogger(WithMaxFieldLength(%d)) succeeded", n)
+		}
+	}
+}
+
+func TestTruncateFieldsByteString(t *testing.T) {
+	c := &truncateCore{max: 100}
+	short := []zap.Field{zap.ByteString("body", []byte(strings.Repeat("a", 100)))}
+	if allocs := testing.AllocsPerRun(100, func() { c.truncateFields(short) }); allocs != 0 {
+		t.Errorf("short byte string: %v allocs, want 0", allocs)
+	}
+
+	b := []byte(strings.Repeat("a", 200))
+	got := c.truncateFields([]zap.Field{zap.ByteString("body", b)})
+	if want := strings.Repeat("a", 100) + truncatedMarker; string(got[0].Interface.([]byte)) != want {
+		t.Errorf("body = %q, want %q", got[0].Interface, want)
+	}
+	if string(b) != strings.Repeat("a", 200) {
+		t.Errorf("caller's bytes changed to %q", b)
+	}
+}
@@ -0,0 +1,22 @@
+package logging
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithUTC formats timestamps in UTC instead of the local time zone, keeping
+// the configured layout; with the default RFC3339 layout they end in "Z".
+// It applies whichever time encoder is set, regardless of option order.
+func WithUTC() Option {
+	return func(o *options) {
+		o.utc = true
+	}
+}
+
+func utcTimeEncoder(enc zapcore.TimeEncoder) zapcore.TimeEncoder {
+	return func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {
+		enc(t.UTC(), pae)
+	}
+}
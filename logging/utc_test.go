@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithUTC(t *testing.T) {
+	// Log in a zone other than UTC, so that local timestamps are told apart.
+	// The clock supplies it rather than time.Local, which other tests'
+	// goroutines read concurrently.
+	now := time.Date(2023, 10, 24, 11, 6, 18, 0, time.FixedZone("CST", 8*60*60))
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		suffix string
+		layout string
+	}{
+		{"local", nil, "+08:00", time.RFC3339},
+		{"utc", []Option{WithUTC()}, "Z", time.RFC3339},
+		{"utc before layout", []Option{WithUTC(), WithTimeLayout(time.RFC3339Nano)}, "Z", time.RFC3339Nano},
+		{"utc after layout", []Option{WithTimeLayout(time.RFC3339Nano), WithUTC()}, "Z", time.RFC3339Nano},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithClock(&stepClock{now: now})}, tt.opts...)
+			logger, entries := newFileLogger(t, opts...)
+			logger.Info("fetched")
+
+			ts, _ := entries()[0]["ts"].(string)
+			if !strings.HasSuffix(ts, tt.suffix) {
+				t.Errorf("ts = %q, want it to end in %q", ts, tt.suffix)
+			}
+			if _, err := time.Parse(tt.layout, ts); err != nil {
+				t.Errorf("ts %q does not use the layout: %v", ts, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldError is the validation failure of a single field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (e FieldError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("field", e.Field)
+	enc.AddString("message", e.Message)
+	return nil
+}
+
+// ValidationErrors logs errs under key as an array of
+// {"field":...,"message":...} objects, encoded without reflection. No errors,
+// nil included, log an empty array rather than null.
+func ValidationErrors(key string, errs []FieldError) zap.Field {
+	return zap.Array(key, fieldErrors(errs))
+}
+
+type fieldErrors []FieldError
+
+func (errs fieldErrors) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, e := range errs {
+		if err := enc.AppendObject(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
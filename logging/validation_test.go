@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		errs []FieldError
+		want interface{}
+	}{
+		{
+			name: "several",
+			errs: []FieldError{
+				{Field: "email", Message: "is not an email address"},
+				{Field: "age", Message: "must be positive"},
+			},
+			want: []interface{}{
+				map[string]interface{}{"field": "email", "message": "is not an email address"},
+				map[string]interface{}{"field": "age", "message": "must be positive"},
+			},
+		},
+		{"empty", []FieldError{}, []interface{}{}},
+		{"nil", nil, []interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t)
+			logger.Info("invalid signup", ValidationErrors("errors", tt.errs))
+
+			if got := entries()[0]["errors"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
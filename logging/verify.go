@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sinkDialTimeout bounds the connection attempt to a network sink.
+const sinkDialTimeout = 5 * time.Second
+
+// VerifySinks checks that every path in cfg.OutputPaths and
+// cfg.ErrorOutputPaths can be written to, without writing anything to them,
+// and returns an error naming each one that cannot, or nil.
+//
+// An existing file is opened for appending and closed again. For a file that
+// does not exist yet, a probe file is created next to it and removed. A URL
+// with a tcp, udp, http or https scheme is connected to and disconnected.
+// "stdout", "stderr" and sinks registered with zap.RegisterSink under other
+// schemes are assumed to work.
+func VerifySinks(cfg zap.Config) error {
+	var errs []error
+	check := func(kind string, paths []string) {
+		for _, path := range paths {
+			if err := verifySink(path); err != nil {
+				errs = append(errs, fmt.Errorf("logging: %s %q is not writable: %w", kind, path, err))
+			}
+		}
+	}
+	check("output", cfg.OutputPaths)
+	check("error output", cfg.ErrorOutputPaths)
+	return errors.Join(errs...)
+}
+
+func verifySink(path string) error {
+	switch path {
+	case "stdout", "stderr":
+		return nil
+	}
+
+	u, err := url.Parse(path)
+	if err != nil || u.Scheme == "" || filepath.VolumeName(path) != "" {
+		// A plain path, like zap.Open treats it.
+		return verifyFile(path)
+	}
+	switch u.Scheme {
+	case "file":
+		return verifyFile(u.Path)
+	case "tcp", "udp":
+		return verifyDial(u.Scheme, u.Host)
+	case "http", "https":
+		port := u.Port()
+		if port == "" {
+			port = u.Scheme // net resolves the service name to 80 or 443
+		}
+		return verifyDial("tcp", net.JoinHostPort(u.Hostname(), port))
+	default:
+		return nil
+	}
+}
+
+func verifyFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err == nil {
+		return f.Close()
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// Zap creates the file on first use, which takes a writable directory.
+	probe, err := os.CreateTemp(filepath.Dir(path), ".logging-probe-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func verifyDial(network, addr string) error {
+	conn, err := net.DialTimeout(network, addr, sinkDialTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestVerifySinks(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.log")
+	if err := os.WriteFile(existing, []byte("kept\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	regular := filepath.Join(dir, "file")
+	if err := os.WriteFile(regular, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"stderr", "stderr", false},
+		{"existing file", existing, false},
+		{"new file", filepath.Join(dir, "new.log"), false},
+		{"file URL", "file://" + filepath.ToSlash(filepath.Join(dir, "url.log")), false},
+		{"missing directory", filepath.Join(dir, "missing", "app.log"), true},
+		{"below a regular file", filepath.Join(regular, "app.log"), true},
+		{"tcp", "tcp://" + ln.Addr().String(), false},
+		{"tcp refused", "tcp://" + closedAddr, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := zap.NewProductionConfig()
+			cfg.OutputPaths = []string{tt.path}
+			err := VerifySinks(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifySinks = %v, want error: %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), `output "`+tt.path+`" is not writable`) {
+				t.Errorf("error %q does not name the path", err)
+			}
+		})
+	}
+
+	// Verifying leaves no trace in the logs or their directory.
+	if b, _ := os.ReadFile(existing); string(b) != "kept\n" {
+		t.Errorf("existing log holds %q after verifying", b)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		t.Errorf("directory holds %v, want only the files the test created", names)
+	}
+}
+
+func TestVerifySinksAggregates(t *testing.T) {
+	regular := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(regular, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := zap.NewProductionConfig()
+	cfg.OutputPaths = []string{"stdout", filepath.Join(regular, "app.log")}
+	cfg.ErrorOutputPaths = []string{filepath.Join(regular, "errors.log")}
+
+	err := VerifySinks(cfg)
+	if err == nil {
+		t.Fatal("VerifySinks succeeded with unwritable paths")
+	}
+	for _, want := range []string{`output "` + cfg.OutputPaths[1], `error output "` + cfg.ErrorOutputPaths[0]} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
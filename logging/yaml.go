@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfigFromYAML reads a zap.Config from the YAML file at path, using the
+// same keys as zap.Config's yaml tags (level, encoding, outputPaths,
+// initialFields, sampling, encoderConfig, ...).
+//
+// The file is decoded on top of NewLogger's defaults, so anything it omits
+// keeps the production setting, including RFC3339 timestamps. An empty
+// outputPaths or errorOutputPaths falls back to stderr.
+func LoadConfigFromYAML(path string) (zap.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return zap.Config{}, fmt.Errorf("logging: read config: %w", err)
+	}
+
+	config := newOptions().config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return zap.Config{}, fmt.Errorf("logging: parse config %s: %w", path, err)
+	}
+	if len(config.OutputPaths) == 0 {
+		config.OutputPaths = []string{"stderr"}
+	}
+	if len(config.ErrorOutputPaths) == 0 {
+		config.ErrorOutputPaths = []string{"stderr"}
+	}
+	return config, nil
+}
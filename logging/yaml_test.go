@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoadConfigFromYAML(t *testing.T) {
+	config, err := LoadConfigFromYAML(filepath.Join("testdata", "config.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfigFromYAML: %v", err)
+	}
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"encoding", config.Encoding, "console"},
+		{"message key", config.EncoderConfig.MessageKey, "message"},
+		{"level key kept", config.EncoderConfig.LevelKey, "level"},
+		{"initial field", config.InitialFields["service"], "demo"},
+		{"output paths", config.OutputPaths[0], "stderr"},
+		{"error output paths", config.ErrorOutputPaths[0], "stderr"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	logger, err := config.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if !logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("logger built from the config does not log at debug")
+	}
+}
+
+func TestLoadConfigFromYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing file", filepath.Join("testdata", "missing.yaml")},
+		{"invalid yaml", filepath.Join("testdata", "invalid.yaml")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadConfigFromYAML(tt.path); err == nil {
+				t.Errorf("LoadConfigFromYAML(%q) succeeded", tt.path)
+			}
+		})
+	}
+}
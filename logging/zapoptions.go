@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithZapOptions applies opts to the built logger, after everything else the
+// other options set up. It gives access to the zap options this package has
+// no option of its own for.
+func WithZapOptions(opts ...zap.Option) Option {
+	return func(o *options) {
+		o.zapOptions = append(o.zapOptions, opts...)
+	}
+}
+
+// SkipCaller reports the caller n frames further up the stack. Loggers used
+// from inside a logging wrapper function need SkipCaller(1) so that entries
+// point at the wrapper's caller instead of the wrapper.
+func SkipCaller(n int) Option {
+	return WithZapOptions(zap.AddCallerSkip(n))
+}
+
+// WithStacktraceLevel captures a stacktrace for entries at level or above,
+// instead of only for Error and above. A level above FatalLevel, such as
+// zapcore.FatalLevel+1, turns stacktraces off.
+func WithStacktraceLevel(level zapcore.Level) Option {
+	return WithZapOptions(zap.AddStacktrace(level))
+}
+
+// StacktraceAt captures a stacktrace for entries at level or above, instead of
+// only for Error and above. It is the same as WithStacktraceLevel.
+func StacktraceAt(level zapcore.Level) Option {
+	return WithStacktraceLevel(level)
+}
+
+// WithClock makes the logger take entry times from clock instead of the
+// system clock, so tests can log with a fixed time. Sampling, which counts
+// entries per second of entry time, follows clock as well.
+func WithClock(clock zapcore.Clock) Option {
+	return WithZapOptions(zap.WithClock(clock))
+}
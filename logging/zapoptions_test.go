@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logFromHelper stands in for a logging wrapper function. It returns its own
+// log call's position, the caller reported when no frames are skipped.
+func logFromHelper(l *zap.Logger) string {
+	l.Info("from helper")
+	return here(-1)
+}
+
+// here returns the base name and line of its caller, as WithShortCaller
+// formats them, offset by delta lines.
+func here(delta int) string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line+delta)
+}
+
+func TestSkipCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// wantHelper is set if the caller should be the helper rather than
+		// the test calling it.
+		wantHelper bool
+	}{
+		{"no skip", nil, true},
+		{"skip 1", []Option{SkipCaller(1)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, append(tt.opts, WithShortCaller())...)
+			want := here(1)
+			helper := logFromHelper(logger)
+			if tt.wantHelper {
+				want = helper
+			}
+
+			if got := entries()[0]["caller"]; got != want {
+				t.Errorf("caller = %v, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestWithZapOptions(t *testing.T) {
+	logger, entries := newFileLogger(t, WithZapOptions(zap.Fields(zap.String("service", "api")), zap.WithCaller(false)))
+	logger.Info("fetched")
+
+	entry := entries()[0]
+	if entry["service"] != "api" {
+		t.Errorf("service = %v, want the field added by zap.Fields", entry["service"])
+	}
+	if _, ok := entry["caller"]; ok {
+		t.Error("entry has a caller despite zap.WithCaller(false)")
+	}
+}
+
+func TestWithStacktraceLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// want lists, for Info, Warn and Error entries in turn, whether the
+		// entry should carry a stacktrace.
+		want [3]bool
+	}{
+		{"default", nil, [3]bool{false, false, true}},
+		{"warn", []Option{WithStacktraceLevel(zapcore.WarnLevel)}, [3]bool{false, true, true}},
+		{"debug", []Option{WithStacktraceLevel(zapcore.DebugLevel)}, [3]bool{true, true, true}},
+		{"disabled", []Option{WithStacktraceLevel(zapcore.FatalLevel + 1)}, [3]bool{false, false, false}},
+		{"StacktraceAt", []Option{StacktraceAt(zapcore.WarnLevel)}, [3]bool{false, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, tt.opts...)
+			logger.Info("info")
+			logger.Warn("warn")
+			logger.Error("error")
+
+			got := entries()
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if _, ok := got[i]["stacktrace"]; ok != want {
+					t.Errorf("%s entry has a stacktrace: %v, want %v", got[i]["level"], ok, want)
+				}
+			}
+		})
+	}
+}
+
+// stepClock is a zapcore.Clock whose time starts at now and moves on by step
+// every time it is read.
+type stepClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func (c *stepClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+func TestWithClock(t *testing.T) {
+	start := time.Date(2023, 10, 24, 11, 6, 18, 0, time.FixedZone("CST", 8*60*60))
+	tests := []struct {
+		name string
+		step time.Duration
+		// want is the number of the 200 identical entries written, which
+		// the production sampler counts per second of clock time.
+		want int
+	}{
+		{"fixed", 0, 101},
+		{"a second per entry", time.Second, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newFileLogger(t, WithClock(&stepClock{now: start, step: tt.step}))
+			for i := 0; i < 200; i++ {
+				logger.Info("same message")
+			}
+
+			got := entries()
+			if len(got) != tt.want {
+				t.Errorf("got %d entries, want %d", len(got), tt.want)
+			}
+			if ts := got[0]["ts"]; ts != "2023-10-24T11:06:18+08:00" {
+				t.Errorf("ts = %v, want the clock's time exactly", ts)
+			}
+		})
+	}
+}